@@ -2,6 +2,7 @@ package abc
 
 import (
 	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/sampling"
 
 	"golang.org/x/exp/rand"
 	"gonum.org/v1/gonum/mat"
@@ -9,31 +10,47 @@ import (
 )
 
 type Params = struct {
-	N_bees        int
-	Abandon_limit int
-	Max_iter      int
+	N_bees            int
+	Abandon_limit     int
+	Max_iter          int
+	Init              sampling.Sampler // initial food sources, nil means uniform
+	Search            Search
+	C                 float64 // GABC, defaults to 1.5
+	Modification_rate float64 // Modified, defaults to 0.4
+	Scaling_factor    float64 // Modified, defaults to 1
+	Chaotic_init      bool    // initial food sources from the logistic map, takes precedence over Init
+	Onlooker          Onlooker
+	Probability       Probability // StandardOnlooker, how onlookers choose a food source
 }
 
 type BeeType = struct {
 	position           mat.Vector
 	value              float64
 	not_improved_since int
-	fitness            float64 // distance to worst
+	fitness            float64 // distance to worst, LegacyOnlooker
 }
 
 type Bee = *BeeType
 
 func Optimize(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
-	employedBees := initBees(f, b_low, b_up, params.N_bees)
+	init := params.Init
+	if params.Chaotic_init {
+		init = chaoticPositions
+	}
+	employedBees := initBees(f, b_low, b_up, params.N_bees, init)
 	best_bee := findBestBee(employedBees)
-	best_position := &best_bee.position
-	best_value := &best_bee.value
+	best_position := best_bee.position
+	best_value := best_bee.value
 	for iter := 0; iter < params.Max_iter; iter++ {
-		doEmployedBeesPhase(f, employedBees, best_position, best_value)
-		doOnLookingPhase(employedBees)
+		doEmployedBeesPhase(f, employedBees, &best_position, &best_value, b_low, b_up, params)
+		if params.Onlooker == LegacyOnlooker {
+			doOnLookingPhase(employedBees)
+		} else {
+			doOnlookerPhase(f, employedBees, &best_position, &best_value, b_low, b_up, params)
+		}
 		doScoutPhase(employedBees, params.Abandon_limit, b_low, b_up, f)
 	}
-	return *best_position
+	return best_position
 }
 
 // abandon bees from position if have not been improved for long
@@ -47,7 +64,7 @@ func doScoutPhase(bees []Bee, abandon_limit int, b_low mat.Vector, b_up mat.Vect
 	}
 }
 
-// re-distribute bees onto other locations and prioritize locations with higher fitness
+// LegacyOnlooker, re-distribute bees onto other locations and prioritize locations with higher fitness
 func doOnLookingPhase(bees []Bee) {
 	computeFitnessOnBees(bees)
 	sumFitness := 0.0
@@ -57,6 +74,10 @@ func doOnLookingPhase(bees []Bee) {
 	positionWeights := make([]float64, len(bees))
 	for i, b := range bees {
 		positionWeights[i] = b.fitness / sumFitness
+		// all bees share the same value
+		if sumFitness == 0.0 {
+			positionWeights[i] = 1.0
+		}
 	}
 	catDist := distuv.NewCategorical(positionWeights, rand.NewSource(uint64(common.GetNextSeed())))
 	for _, b := range bees {
@@ -88,29 +109,28 @@ func findValueBounds(bees []Bee) (float64, float64) {
 }
 
 // search in local neighborhood for better value
-func doEmployedBeesPhase(f common.Target, bees []Bee, best_position *mat.Vector, best_value *float64) {
+func doEmployedBeesPhase(f common.Target, bees []Bee, best_position *mat.Vector, best_value *float64,
+	b_low mat.Vector, b_up mat.Vector, params *Params) {
 	ran := common.GetNewRand()
-	for _, b := range bees {
-		k := ran.Intn(len(bees))
-		i := ran.Intn(b.position.Len())
-		phi := -1.0 + 2.0*ran.Float64()
-		v := mat.NewVecDense(b.position.Len(), nil)
-		v.CopyVec(b.position)
-		x_i := b.position.AtVec(i)
-		x_k := bees[k].position.AtVec(i)
-		v.SetVec(i, x_i+phi*(x_i-x_k))
-		value := f(v)
-		if value < b.value {
-			b.position = v
-			b.value = value
-			b.not_improved_since = 0
-		} else {
-			b.not_improved_since = b.not_improved_since + 1
-		}
-		if b.value < *best_value {
-			*best_value = b.value
-			*best_position = b.position
-		}
+	for i, b := range bees {
+		v := searchCandidate(bees, i, *best_position, b_low, b_up, params, ran)
+		tryCandidate(f, b, v, best_position, best_value)
+	}
+}
+
+// greedy selection between the food source of b and the candidate v, counting trials without improvement
+func tryCandidate(f common.Target, b Bee, v *mat.VecDense, best_position *mat.Vector, best_value *float64) {
+	value := f(v)
+	if value < b.value {
+		b.position = v
+		b.value = value
+		b.not_improved_since = 0
+	} else {
+		b.not_improved_since = b.not_improved_since + 1
+	}
+	if b.value < *best_value {
+		*best_value = b.value
+		*best_position = b.position
 	}
 }
 
@@ -124,10 +144,9 @@ func findBestBee(bees []Bee) Bee {
 	return best
 }
 
-func initBees(f common.Target, b_low mat.Vector, b_up mat.Vector, n_bees int) []Bee {
+func initBees(f common.Target, b_low mat.Vector, b_up mat.Vector, n_bees int, init sampling.Sampler) []Bee {
 	res := make([]Bee, n_bees)
-	for i := 0; i < n_bees; i++ {
-		position := common.RandomDataInBounds(b_low, b_up)
+	for i, position := range sampling.Sample(init, b_low, b_up, n_bees) {
 		res[i] = &BeeType{
 			position: position,
 			value:    f(position)}
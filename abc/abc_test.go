@@ -4,6 +4,8 @@ import (
 	"math"
 	"testing"
 
+	"github.com/applied-math-coding/heuristic/perm"
+
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -23,3 +25,114 @@ func TestAbc(t *testing.T) {
 	t.Log(min)
 	t.Log(f(min))
 }
+
+func TestAbcSearch(t *testing.T) {
+	n := 10
+	b_low := mat.NewVecDense(n, nil)
+	b_up := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		b_low.SetVec(i, -5.12)
+		b_up.SetVec(i, 5.12)
+	}
+	rastrigin := func(x mat.Vector) float64 {
+		res := 10.0 * float64(x.Len())
+		for i := 0; i < x.Len(); i++ {
+			res = res + math.Pow(x.AtVec(i), 2.0) - 10.0*math.Cos(2.0*math.Pi*x.AtVec(i))
+		}
+		return res
+	}
+	for _, search := range []Search{Basic, GABC, BestABC, Modified} {
+		for _, chaotic := range []bool{false, true} {
+			params := &Params{
+				N_bees:        40,
+				Abandon_limit: 100,
+				Max_iter:      500,
+				Search:        search,
+				Chaotic_init:  chaotic}
+			min := Optimize(rastrigin, b_low, b_up, params)
+			t.Log(search, chaotic, rastrigin(min))
+		}
+	}
+}
+
+func TestAbcOnlooker(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	onlookers := []Onlooker{StandardOnlooker, StandardOnlooker, LegacyOnlooker}
+	probabilities := []Probability{FitnessProbability, RankProbability, FitnessProbability}
+	for k := range onlookers {
+		params := &Params{
+			N_bees:        20,
+			Abandon_limit: 20,
+			Max_iter:      100,
+			Onlooker:      onlookers[k],
+			Probability:   probabilities[k]}
+		min := Optimize(f, b_low, b_up, params)
+		t.Log(onlookers[k], probabilities[k], min, f(min))
+	}
+}
+
+func TestSourceWeights(t *testing.T) {
+	values := []float64{3.0, -1.0, 3.0}
+	fitness := sourceWeights(values, FitnessProbability)
+	if fitness[0] != 0.25 || fitness[1] != 2.0 || fitness[2] != 0.25 {
+		t.Fatal(fitness)
+	}
+	rank := sourceWeights(values, RankProbability)
+	if rank[1] != 3.0 || rank[0] != 2.0 || rank[2] != 1.0 {
+		t.Fatal(rank)
+	}
+}
+
+func TestOptimizeBinary(t *testing.T) {
+	target := []bool{true, false, true, true, false, false, true, false, true, true, false, true, false, false, true, true,
+		false, true, true, false}
+	f := func(x []bool) float64 {
+		res := 0.0
+		for i, e := range x {
+			if e != target[i] {
+				res++
+			}
+		}
+		return res
+	}
+	params := &Params{
+		N_bees:        20,
+		Abandon_limit: 20,
+		Max_iter:      100}
+	min := OptimizeBinary(f, len(target), params)
+	t.Log(min, f(min))
+	if f(min) != 0.0 {
+		t.Fatal("target not found", min)
+	}
+}
+
+func TestOptimizePermutation(t *testing.T) {
+	n := 12
+	dist := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a := 2.0 * math.Pi * float64(i) / float64(n)
+			b := 2.0 * math.Pi * float64(j) / float64(n)
+			dist.Set(i, j, math.Hypot(math.Cos(a)-math.Cos(b), math.Sin(a)-math.Sin(b)))
+		}
+	}
+	f := func(p perm.Permutation) float64 {
+		return perm.TourLength(dist, p)
+	}
+	params := &Params{
+		N_bees:        20,
+		Abandon_limit: 50,
+		Max_iter:      300}
+	min := OptimizePermutation(f, n, params)
+	optimum := float64(n) * dist.At(0, 1)
+	t.Log(min, f(min), optimum)
+	if f(min) > optimum+1e-9 {
+		t.Fatal("optimal tour not found", min)
+	}
+}
@@ -0,0 +1,145 @@
+package abc
+
+import (
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/perm"
+)
+
+type BinaryTarget = func(x []bool) float64
+type PermutationTarget = func(p perm.Permutation) float64
+
+// food source of the discrete variants, solution is []bool or perm.Permutation
+type source = struct {
+	solution           interface{}
+	value              float64
+	not_improved_since int
+}
+
+// discrete problem, neighbour creates a candidate around x guided by the source k
+type space = struct {
+	random    func(r *rand.Rand) interface{}
+	neighbour func(x interface{}, k interface{}, r *rand.Rand) interface{}
+	evaluate  func(s interface{}) float64
+}
+
+// OptimizeBinary searches a minimum of f over bitstrings of length n, e.g. for feature selection.
+// A candidate takes each bit in which the source differs from a random other source with probability 1/2
+// (v = x XOR (phi AND (x XOR x_k)), Kiran, Gunduz 2013) and flips a random bit if nothing changed.
+// N_bees, Abandon_limit, Max_iter and Probability are used as in Optimize, the onlooker phase is StandardOnlooker.
+func OptimizeBinary(f BinaryTarget, n int, params *Params) []bool {
+	s := &space{
+		random: func(r *rand.Rand) interface{} {
+			res := make([]bool, n)
+			for i := range res {
+				res[i] = r.Intn(2) == 1
+			}
+			return res
+		},
+		neighbour: func(x interface{}, k interface{}, r *rand.Rand) interface{} {
+			a, b := x.([]bool), k.([]bool)
+			res := make([]bool, n)
+			changed := false
+			for i := range res {
+				res[i] = a[i]
+				if a[i] != b[i] && r.Intn(2) == 1 {
+					res[i] = !a[i]
+					changed = true
+				}
+			}
+			if !changed {
+				i := r.Intn(n)
+				res[i] = !res[i]
+			}
+			return res
+		},
+		evaluate: func(x interface{}) float64 {
+			return f(x.([]bool))
+		}}
+	return optimizeDiscrete(s, params).([]bool)
+}
+
+// OptimizePermutation searches a minimum of f over permutations of 0..n-1, e.g. for scheduling.
+// A candidate moves the element which a random other source has at a random position j to position j
+// (an insert move towards that source), or applies a random insert move if both agree at j.
+// N_bees, Abandon_limit, Max_iter and Probability are used as in Optimize, the onlooker phase is StandardOnlooker.
+func OptimizePermutation(f PermutationTarget, n int, params *Params) perm.Permutation {
+	s := &space{
+		random: func(r *rand.Rand) interface{} {
+			return perm.Random(n, r)
+		},
+		neighbour: func(x interface{}, k interface{}, r *rand.Rand) interface{} {
+			a, b := x.(perm.Permutation), k.(perm.Permutation)
+			j := r.Intn(n)
+			i := 0
+			for a[i] != b[j] {
+				i++
+			}
+			if i == j {
+				i = r.Intn(n)
+			}
+			return perm.Insert(a, i, j)
+		},
+		evaluate: func(x interface{}) float64 {
+			return f(x.(perm.Permutation))
+		}}
+	return optimizeDiscrete(s, params).(perm.Permutation)
+}
+
+// employed, onlooker and scout phases of Optimize on a discrete space
+func optimizeDiscrete(s *space, params *Params) interface{} {
+	r := common.GetNewRand()
+	sources := make([]*source, params.N_bees)
+	for i := range sources {
+		sources[i] = randomSource(s, r)
+	}
+	best := *sources[0]
+	for _, e := range sources {
+		if e.value < best.value {
+			best = *e
+		}
+	}
+	try := func(i int) {
+		e := sources[i]
+		v := s.neighbour(e.solution, sources[otherBee(len(sources), r, i)].solution, r)
+		value := s.evaluate(v)
+		if value < e.value {
+			e.solution = v
+			e.value = value
+			e.not_improved_since = 0
+		} else {
+			e.not_improved_since = e.not_improved_since + 1
+		}
+		if e.value < best.value {
+			best = *e
+		}
+	}
+	for iter := 0; iter < params.Max_iter; iter++ {
+		for i := range sources {
+			try(i)
+		}
+		values := make([]float64, len(sources))
+		for i, e := range sources {
+			values[i] = e.value
+		}
+		weights := sourceWeights(values, params.Probability)
+		for k := 0; k < len(sources); k++ {
+			try(chooseSource(weights, r))
+		}
+		for i, e := range sources {
+			if e.not_improved_since > params.Abandon_limit {
+				sources[i] = randomSource(s, r)
+				if sources[i].value < best.value {
+					best = *sources[i]
+				}
+			}
+		}
+	}
+	return best.solution
+}
+
+func randomSource(s *space, r *rand.Rand) *source {
+	x := s.random(r)
+	return &source{solution: x, value: s.evaluate(x)}
+}
@@ -0,0 +1,76 @@
+package abc
+
+import (
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Onlooker selects the onlooker phase of Optimize.
+type Onlooker int
+
+const (
+	StandardOnlooker Onlooker = iota // onlookers choose food sources by Probability and search around them (Karaboga 2005)
+	LegacyOnlooker                   // bees take over the positions of fitter bees without searching
+)
+
+// Probability determines how likely an onlooker chooses a food source.
+type Probability int
+
+const (
+	FitnessProbability Probability = iota // proportional to 1/(1+f), or 1+|f| for negative f
+	RankProbability                       // linear in the rank, the best source has weight N_bees and the worst 1
+)
+
+// every onlooker chooses a food source and searches a candidate around it like an employed bee
+func doOnlookerPhase(f common.Target, bees []Bee, best_position *mat.Vector, best_value *float64,
+	b_low mat.Vector, b_up mat.Vector, params *Params) {
+	r := common.GetNewRand()
+	weights := sourceWeights(beeValues(bees), params.Probability)
+	for k := 0; k < len(bees); k++ {
+		i := chooseSource(weights, r)
+		v := searchCandidate(bees, i, *best_position, b_low, b_up, params, r)
+		tryCandidate(f, bees[i], v, best_position, best_value)
+	}
+}
+
+// weights are computed once per phase, before the onlookers change the sources
+func sourceWeights(values []float64, probability Probability) []float64 {
+	res := make([]float64, len(values))
+	if probability == RankProbability {
+		for rank, i := range common.SelectBest(values, len(values)) {
+			res[i] = float64(len(values) - rank)
+		}
+		return res
+	}
+	for i, v := range values {
+		res[i] = common.Fitness(v)
+	}
+	return res
+}
+
+// chooseSource draws an index with probability proportional to the weights
+func chooseSource(weights []float64, r *rand.Rand) int {
+	sum := 0.0
+	for _, w := range weights {
+		sum = sum + w
+	}
+	u := r.Float64() * sum
+	for i, w := range weights {
+		if u < w {
+			return i
+		}
+		u = u - w
+	}
+	return len(weights) - 1
+}
+
+func beeValues(bees []Bee) []float64 {
+	res := make([]float64, len(bees))
+	for i, b := range bees {
+		res[i] = b.value
+	}
+	return res
+}
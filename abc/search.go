@@ -0,0 +1,119 @@
+package abc
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Search is the equation by which a bee creates a candidate food source v from its source x.
+// phi is uniform in [-1, 1], k, r_1 and r_2 are random bees different from the bee itself.
+type Search int
+
+const (
+	Basic    Search = iota // v_j = x_j + phi (x_j - x_k,j) on one random dimension j (Karaboga 2005)
+	GABC                   // Basic + psi (best_j - x_j), psi uniform in [0, C] (Zhu, Kwong 2010)
+	BestABC                // v_j = best_j + phi (x_r1,j - x_r2,j), ABC/best/1 (Gao, Liu, Huang 2012)
+	Modified               // Basic on each dimension with probability Modification_rate, phi in [-Scaling_factor, Scaling_factor] (Akay, Karaboga 2012)
+)
+
+// searchCandidate applies the search equation of params to bee i
+func searchCandidate(bees []Bee, i int, best mat.Vector, b_low mat.Vector, b_up mat.Vector,
+	params *Params, r *rand.Rand) *mat.VecDense {
+	x := bees[i].position
+	n := x.Len()
+	v := mat.NewVecDense(n, nil)
+	v.CopyVec(x)
+	j := r.Intn(n)
+	k := otherBee(len(bees), r, i)
+	phi := -1.0 + 2.0*r.Float64()
+	switch params.Search {
+	case GABC:
+		psi := r.Float64() * gabcC(params)
+		v.SetVec(j, x.AtVec(j)+phi*(x.AtVec(j)-bees[k].position.AtVec(j))+psi*(best.AtVec(j)-x.AtVec(j)))
+	case BestABC:
+		r_2 := otherBee(len(bees), r, i, k)
+		v.SetVec(j, best.AtVec(j)+phi*(bees[k].position.AtVec(j)-bees[r_2].position.AtVec(j)))
+	case Modified:
+		mr := modificationRate(params)
+		sf := scalingFactor(params)
+		for d := 0; d < n; d++ {
+			if d == j || r.Float64() < mr {
+				phi = sf * (-1.0 + 2.0*r.Float64())
+				v.SetVec(d, x.AtVec(d)+phi*(x.AtVec(d)-bees[k].position.AtVec(d)))
+			}
+		}
+	default:
+		v.SetVec(j, x.AtVec(j)+phi*(x.AtVec(j)-bees[k].position.AtVec(j)))
+	}
+	common.ApplyElementwise(v, func(e float64, idx int) float64 {
+		return math.Min(math.Max(e, b_low.AtVec(idx)), b_up.AtVec(idx))
+	})
+	return v
+}
+
+// returns a random bee not contained in exclude, if there is one
+func otherBee(n int, r *rand.Rand, exclude ...int) int {
+	if n <= len(exclude) {
+		return r.Intn(n)
+	}
+	for {
+		k := r.Intn(n)
+		excluded := false
+		for _, e := range exclude {
+			excluded = excluded || e == k
+		}
+		if !excluded {
+			return k
+		}
+	}
+}
+
+// chaoticPositions iterates the logistic map per dimension and maps it into the bounds (Alatas 2010)
+func chaoticPositions(b_low mat.Vector, b_up mat.Vector, n int) []*mat.VecDense {
+	r := common.GetNewRand()
+	d := b_low.Len()
+	z := make([]float64, d)
+	for j := range z {
+		// fixed points and the pre-images of 0 of the logistic map are avoided
+		z[j] = 0.1 + 0.8*r.Float64()
+		for z[j] == 0.25 || z[j] == 0.5 || z[j] == 0.75 {
+			z[j] = 0.1 + 0.8*r.Float64()
+		}
+	}
+	res := make([]*mat.VecDense, n)
+	for i := range res {
+		res[i] = mat.NewVecDense(d, nil)
+		for j := range z {
+			for k := 0; k < 300; k++ {
+				z[j] = 4.0 * z[j] * (1.0 - z[j])
+			}
+			res[i].SetVec(j, b_low.AtVec(j)+z[j]*(b_up.AtVec(j)-b_low.AtVec(j)))
+		}
+	}
+	return res
+}
+
+func gabcC(params *Params) float64 {
+	if params.C > 0.0 {
+		return params.C
+	}
+	return 1.5
+}
+
+func modificationRate(params *Params) float64 {
+	if params.Modification_rate > 0.0 {
+		return params.Modification_rate
+	}
+	return 0.4
+}
+
+func scalingFactor(params *Params) float64 {
+	if params.Scaling_factor > 0.0 {
+		return params.Scaling_factor
+	}
+	return 1.0
+}
@@ -0,0 +1,290 @@
+package aco
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/perm"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Variant int
+
+const (
+	AntSystem       Variant = iota
+	MaxMinAntSystem         // only the best ant deposits, pheromone is kept within [tau_min, tau_max]
+	AntColonySystem         // pseudo-random-proportional rule with local and global-best updates
+)
+
+type Construction int
+
+const (
+	Tour       Construction = iota // pheromone (i,j) rewards visiting j right after i (TSP)
+	Assignment                     // pheromone (i,j) rewards p[i] = j (QAP)
+)
+
+type Problem = struct {
+	N            int
+	Cost         func(p perm.Permutation) float64
+	Visibility   mat.Matrix // heuristic desirability of (i,j), nil means no heuristic information
+	Construction Construction
+	Symmetric    bool // Tour pheromone is deposited on (i,j) and (j,i)
+}
+
+type Params = struct {
+	N_ants       int
+	Max_iter     int
+	Variant      Variant
+	Alpha        float64 // weight of pheromone
+	Beta         float64 // weight of visibility
+	Rho          float64 // evaporation rate
+	Q            float64 // deposit of AntSystem is Q / cost, defaults to 1
+	Q_0          float64 // probability of AntColonySystem to take the best choice
+	Xi           float64 // local evaporation of AntColonySystem
+	P_best       float64 // MaxMinAntSystem, determines tau_min, defaults to 0.05
+	Local_search func(p perm.Permutation) perm.Permutation
+}
+
+type colony = struct {
+	tau     *mat.Dense
+	tau_0   float64
+	tau_min float64
+	tau_max float64
+}
+
+// NewTSP creates a routing problem. The cost of a permutation is the length of the closed tour.
+func NewTSP(dist mat.Matrix) *Problem {
+	n, _ := dist.Dims()
+	visibility := mat.NewDense(n, n, nil)
+	symmetric := true
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			visibility.Set(i, j, 1.0/math.Max(dist.At(i, j), common.EPSILON))
+			symmetric = symmetric && dist.At(i, j) == dist.At(j, i)
+		}
+	}
+	return &Problem{
+		N:            n,
+		Cost:         func(p perm.Permutation) float64 { return perm.TourLength(dist, p) },
+		Visibility:   visibility,
+		Construction: Tour,
+		Symmetric:    symmetric}
+}
+
+// NewQAP creates a quadratic assignment problem. The visibility prefers to put facilities
+// with large total flow onto locations with small total distance.
+func NewQAP(flow mat.Matrix, dist mat.Matrix) *Problem {
+	n, _ := flow.Dims()
+	flow_potential := make([]float64, n)
+	dist_potential := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			flow_potential[i] = flow_potential[i] + flow.At(i, j)
+			dist_potential[i] = dist_potential[i] + dist.At(i, j)
+		}
+	}
+	visibility := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			visibility.Set(i, j, 1.0/math.Max(flow_potential[i]*dist_potential[j], common.EPSILON))
+		}
+	}
+	return &Problem{
+		N:            n,
+		Cost:         func(p perm.Permutation) float64 { return perm.AssignmentCost(flow, dist, p) },
+		Visibility:   visibility,
+		Construction: Assignment}
+}
+
+// Optimize returns the best permutation found and its cost (e.g. the tour length).
+func Optimize(problem *Problem, params *Params) (perm.Permutation, float64) {
+	r := common.GetNewRand()
+	best := construct(problem, nil, 0.0, 1.0, params, r)
+	best_cost := problem.Cost(best)
+	c := initColony(problem, best_cost, params)
+	for iter := 0; iter < params.Max_iter; iter++ {
+		ants := make([]perm.Permutation, params.N_ants)
+		costs := make([]float64, params.N_ants)
+		iter_best := 0
+		for k := range ants {
+			ants[k] = construct(problem, c, params.Alpha, params.Q_0, params, r)
+			if params.Local_search != nil {
+				ants[k] = params.Local_search(ants[k])
+			}
+			costs[k] = problem.Cost(ants[k])
+			if costs[k] < costs[iter_best] {
+				iter_best = k
+			}
+		}
+		if costs[iter_best] < best_cost {
+			best = perm.Copy(ants[iter_best])
+			best_cost = costs[iter_best]
+		}
+		switch params.Variant {
+		case MaxMinAntSystem:
+			evaporate(c, params.Rho)
+			updateLimits(c, problem.N, best_cost, params)
+			// mostly iteration-best, from time to time the best-so-far ant deposits
+			if iter%5 == 4 {
+				deposit(c, problem, best, 1.0/best_cost, 1.0)
+			} else {
+				deposit(c, problem, ants[iter_best], 1.0/costs[iter_best], 1.0)
+			}
+			clampPheromone(c)
+		case AntColonySystem:
+			deposit(c, problem, best, params.Rho/best_cost, 1.0-params.Rho)
+		default:
+			evaporate(c, params.Rho)
+			q := params.Q
+			if q <= 0.0 {
+				q = 1.0
+			}
+			for k, a := range ants {
+				deposit(c, problem, a, q/costs[k], 1.0)
+			}
+		}
+	}
+	return best, best_cost
+}
+
+func initColony(problem *Problem, cost float64, params *Params) *colony {
+	n := problem.N
+	c := &colony{}
+	switch params.Variant {
+	case MaxMinAntSystem:
+		updateLimits(c, n, cost, params)
+		c.tau_0 = c.tau_max
+	case AntColonySystem:
+		c.tau_0 = 1.0 / (float64(n) * cost)
+	default:
+		c.tau_0 = float64(params.N_ants) / cost
+	}
+	c.tau = mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			c.tau.Set(i, j, c.tau_0)
+		}
+	}
+	return c
+}
+
+// builds a permutation step by step. Without a colony only the visibility is used (greedy construction).
+func construct(problem *Problem, c *colony, alpha float64, q_0 float64,
+	params *Params, r *rand.Rand) perm.Permutation {
+	n := problem.N
+	p := make(perm.Permutation, 0, n)
+	used := make([]bool, n)
+	weights := make([]float64, n)
+	if problem.Construction == Tour {
+		start := r.Intn(n)
+		p = append(p, start)
+		used[start] = true
+	}
+	for len(p) < n {
+		i := len(p)
+		if problem.Construction == Tour {
+			i = p[len(p)-1]
+		}
+		for j := 0; j < n; j++ {
+			weights[j] = 0.0
+			if used[j] {
+				continue
+			}
+			weights[j] = 1.0
+			if c != nil {
+				weights[j] = math.Pow(c.tau.At(i, j), alpha)
+			}
+			if problem.Visibility != nil {
+				weights[j] = weights[j] * math.Pow(problem.Visibility.At(i, j), params.Beta)
+			}
+		}
+		var j int
+		if c == nil || (params.Variant == AntColonySystem && r.Float64() < q_0) {
+			j = argMax(weights, used)
+		} else {
+			j = spinWheel(weights, used, r)
+		}
+		if c != nil && params.Variant == AntColonySystem {
+			c.tau.Set(i, j, (1.0-params.Xi)*c.tau.At(i, j)+params.Xi*c.tau_0)
+			if problem.Construction == Tour && problem.Symmetric {
+				c.tau.Set(j, i, c.tau.At(i, j))
+			}
+		}
+		p = append(p, j)
+		used[j] = true
+	}
+	return p
+}
+
+func argMax(weights []float64, used []bool) int {
+	res := -1
+	for j, w := range weights {
+		if !used[j] && (res < 0 || w > weights[res]) {
+			res = j
+		}
+	}
+	return res
+}
+
+func spinWheel(weights []float64, used []bool, r *rand.Rand) int {
+	sum := 0.0
+	for _, w := range weights {
+		sum = sum + w
+	}
+	if sum <= 0.0 {
+		return argMax(weights, used)
+	}
+	u := r.Float64() * sum
+	last := -1
+	for j, w := range weights {
+		if used[j] {
+			continue
+		}
+		last = j
+		u = u - w
+		if u <= 0.0 {
+			return j
+		}
+	}
+	return last
+}
+
+func evaporate(c *colony, rho float64) {
+	c.tau.Scale(1.0-rho, c.tau)
+}
+
+// tau = keep * tau + amount on all components of p
+func deposit(c *colony, problem *Problem, p perm.Permutation, amount float64, keep float64) {
+	update := func(i int, j int) {
+		c.tau.Set(i, j, keep*c.tau.At(i, j)+amount)
+	}
+	for k := range p {
+		if problem.Construction == Assignment {
+			update(k, p[k])
+			continue
+		}
+		i, j := p[k], p[(k+1)%len(p)]
+		update(i, j)
+		if problem.Symmetric {
+			update(j, i)
+		}
+	}
+}
+
+func updateLimits(c *colony, n int, best_cost float64, params *Params) {
+	p_best := params.P_best
+	if p_best <= 0.0 {
+		p_best = 0.05
+	}
+	c.tau_max = 1.0 / (params.Rho * best_cost)
+	root := math.Pow(p_best, 1.0/float64(n))
+	c.tau_min = c.tau_max * (1.0 - root) / (math.Max(float64(n)/2.0-1.0, 1.0) * root)
+}
+
+func clampPheromone(c *colony) {
+	c.tau.Apply(func(i int, j int, v float64) float64 {
+		return math.Min(math.Max(v, c.tau_min), c.tau_max)
+	}, c.tau)
+}
@@ -0,0 +1,77 @@
+package aco
+
+import (
+	"math"
+	"testing"
+
+	"github.com/applied-math-coding/heuristic/perm"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// cities on the unit circle in shuffled order, the optimal tour is slightly shorter than 2*pi
+func createCircle(n int) *mat.Dense {
+	dist := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a := 2.0 * math.Pi * float64((i*7)%n) / float64(n)
+			b := 2.0 * math.Pi * float64((j*7)%n) / float64(n)
+			dist.Set(i, j, math.Hypot(math.Cos(a)-math.Cos(b), math.Sin(a)-math.Sin(b)))
+		}
+	}
+	return dist
+}
+
+func TestAco(t *testing.T) {
+	problem := NewTSP(createCircle(20))
+	for _, variant := range []Variant{AntSystem, MaxMinAntSystem, AntColonySystem} {
+		params := &Params{
+			N_ants:   20,
+			Max_iter: 50,
+			Variant:  variant,
+			Alpha:    1.0,
+			Beta:     2.0,
+			Rho:      0.1,
+			Q_0:      0.9,
+			Xi:       0.1}
+		tour, length := Optimize(problem, params)
+		t.Log(tour)
+		t.Log(length)
+	}
+}
+
+func TestAcoTwoOpt(t *testing.T) {
+	dist := createCircle(30)
+	params := &Params{
+		N_ants:   10,
+		Max_iter: 10,
+		Variant:  MaxMinAntSystem,
+		Alpha:    1.0,
+		Beta:     2.0,
+		Rho:      0.02,
+		Local_search: func(p perm.Permutation) perm.Permutation {
+			return perm.TwoOpt(dist, p)
+		}}
+	_, length := Optimize(NewTSP(dist), params)
+	t.Log(length)
+	if length > 2.0*math.Pi {
+		t.Fatal("TestAcoTwoOpt fails")
+	}
+}
+
+func TestAcoQap(t *testing.T) {
+	flow := mat.NewDense(4, 4, []float64{
+		0, 5, 2, 4,
+		5, 0, 3, 0,
+		2, 3, 0, 0,
+		4, 0, 0, 0})
+	dist := mat.NewDense(4, 4, []float64{
+		0, 22, 53, 53,
+		22, 0, 40, 62,
+		53, 40, 0, 55,
+		53, 62, 55, 0})
+	params := &Params{N_ants: 10, Max_iter: 20, Variant: AntSystem, Alpha: 1.0, Beta: 1.0, Rho: 0.1}
+	p, cost := Optimize(NewQAP(flow, dist), params)
+	t.Log(p)
+	t.Log(cost)
+}
@@ -0,0 +1,172 @@
+package bayesopt
+
+import (
+	"math"
+
+	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/de"
+	"github.com/applied-math-coding/heuristic/pso"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type Kernel int
+
+const (
+	RBF Kernel = iota
+	Matern32
+	Matern52
+)
+
+type Acquisition int
+
+const (
+	EI  Acquisition = iota // expected improvement
+	UCB                    // lower confidence bound mu - Kappa * sigma, as we minimize
+	PI                     // probability of improvement
+)
+
+type Inner int
+
+const (
+	DE Inner = iota
+	PSO
+)
+
+// Only Max_evals evaluations of f are spent, N_init (at least 2) of them at random points.
+// The acquisition is maximized by de or pso. If DE_params or PSO_params are nil, small default settings are used.
+type Params = struct {
+	N_init      int
+	Max_evals   int
+	Batch_size  int // points proposed at once by the constant-liar strategy, defaults to 1
+	Kernel      Kernel
+	Acquisition Acquisition
+	Kappa       float64 // UCB, defaults to 2
+	Xi          float64 // improvement margin of EI and PI
+	Inner       Inner
+	DE_params   *de.Params
+	PSO_params  *pso.Params
+}
+
+// Optimize searches a minimum of an expensive f by means of a Gaussian-process surrogate.
+func Optimize(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
+	n := b_low.Len()
+	batch_size := params.Batch_size
+	if batch_size <= 0 {
+		batch_size = 1
+	}
+	n_init := params.N_init
+	if n_init < 2 {
+		n_init = 2
+	}
+	unit_low := mat.NewVecDense(n, nil)
+	unit_up := ones(n)
+	x := make([]mat.Vector, 0, params.Max_evals)
+	y := make([]float64, 0, params.Max_evals)
+	best_idx := 0
+	evaluate := func(u mat.Vector) {
+		u = clampUnit(u)
+		x = append(x, u)
+		y = append(y, f(toOuter(u, b_low, b_up)))
+		if y[len(y)-1] < y[best_idx] {
+			best_idx = len(y) - 1
+		}
+	}
+	for i := 0; i < n_init && i < params.Max_evals; i++ {
+		evaluate(common.RandomDataInBounds(unit_low, unit_up))
+	}
+	g := newGP(params.Kernel, n)
+	for len(x) < params.Max_evals {
+		fit(g, x, y, true)
+		// constant liar: pretend the pending points returned the best value seen so far
+		lie_x := append([]mat.Vector(nil), x...)
+		lie_y := append([]float64(nil), y...)
+		batch := make([]mat.Vector, 0, batch_size)
+		for k := 0; k < batch_size && len(x)+len(batch) < params.Max_evals; k++ {
+			if k > 0 {
+				fit(g, lie_x, lie_y, false)
+			}
+			u := maximizeAcquisition(g, y[best_idx], unit_low, unit_up, params)
+			batch = append(batch, u)
+			lie_x = append(lie_x, u)
+			lie_y = append(lie_y, y[best_idx])
+		}
+		for _, u := range batch {
+			evaluate(u)
+		}
+	}
+	return toOuter(x[best_idx], b_low, b_up)
+}
+
+func maximizeAcquisition(g *gp, best float64, unit_low mat.Vector, unit_up mat.Vector, params *Params) mat.Vector {
+	target := func(u mat.Vector) float64 {
+		return -acquisition(g, u, best, params)
+	}
+	if params.Inner == PSO {
+		pso_params := params.PSO_params
+		if pso_params == nil {
+			pso_params = &pso.Params{
+				N_particles:  30,
+				Max_iter:     50,
+				Omega:        0.7,
+				Phi_p:        1.5,
+				Phi_g:        1.5,
+				LearningRate: 1.0}
+		}
+		return pso.Optimize(target, unit_low, unit_up, pso_params)
+	}
+	de_params := params.DE_params
+	if de_params == nil {
+		de_params = &de.Params{N_agents: 30, Max_iter: 50, F: 0.8, CR: 0.9}
+	}
+	return de.Optimize(target, unit_low, unit_up, de_params)
+}
+
+func acquisition(g *gp, u mat.Vector, best float64, params *Params) float64 {
+	mu, sigma := predict(g, u)
+	if params.Acquisition == UCB {
+		kappa := params.Kappa
+		if kappa <= 0.0 {
+			kappa = 2.0
+		}
+		return -(mu - kappa*sigma)
+	}
+	improvement := best - mu - params.Xi
+	if sigma <= 0.0 {
+		if params.Acquisition == PI {
+			return 0.0
+		}
+		return math.Max(improvement, 0.0)
+	}
+	z := improvement / sigma
+	if params.Acquisition == PI {
+		return distuv.UnitNormal.CDF(z)
+	}
+	return improvement*distuv.UnitNormal.CDF(z) + sigma*distuv.UnitNormal.Prob(z)
+}
+
+func toOuter(u mat.Vector, b_low mat.Vector, b_up mat.Vector) mat.Vector {
+	x := mat.NewVecDense(u.Len(), nil)
+	x.SubVec(b_up, b_low)
+	x.MulElemVec(x, u)
+	x.AddVec(x, b_low)
+	return x
+}
+
+// projects u onto the unit cube, as the inner optimizer may return points slightly outside
+func clampUnit(u mat.Vector) mat.Vector {
+	res := mat.VecDenseCopyOf(u)
+	common.ApplyElementwise(res, func(e float64, idx int) float64 {
+		return math.Min(math.Max(e, 0.0), 1.0)
+	})
+	return res
+}
+
+func ones(n int) *mat.VecDense {
+	v := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		v.SetVec(i, 1.0)
+	}
+	return v
+}
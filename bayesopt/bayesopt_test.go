@@ -0,0 +1,85 @@
+package bayesopt
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBayesopt(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	evals := 0
+	f := func(x mat.Vector) float64 {
+		evals++
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	for _, acquisition := range []Acquisition{EI, UCB, PI} {
+		evals = 0
+		params := &Params{
+			N_init:      10,
+			Max_evals:   40,
+			Batch_size:  3,
+			Kernel:      Matern52,
+			Acquisition: acquisition,
+			Xi:          0.01}
+		min := Optimize(f, b_low, b_up, params)
+		t.Log(min)
+		t.Log(f(min))
+		if evals != params.Max_evals+1 {
+			t.Fatal("TestBayesopt exceeds budget")
+		}
+	}
+}
+
+func TestBayesoptBounds(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{0.0, 0.0})
+	b_up := mat.NewVecDense(2, []float64{1.0, 1.0})
+	// the minimum lies in a corner, so the acquisition maximizer is pushed against the bounds
+	f := func(x mat.Vector) float64 {
+		for i := 0; i < x.Len(); i++ {
+			if x.AtVec(i) < b_low.AtVec(i) || x.AtVec(i) > b_up.AtVec(i) {
+				t.Fatal("evaluation outside the bounds", x)
+			}
+		}
+		return -x.AtVec(0) - x.AtVec(1)
+	}
+	for _, inner := range []Inner{DE, PSO} {
+		Optimize(f, b_low, b_up, &Params{N_init: 5, Max_evals: 30, Inner: inner})
+	}
+}
+
+func TestGP(t *testing.T) {
+	x := []mat.Vector{
+		mat.NewVecDense(1, []float64{0.0}),
+		mat.NewVecDense(1, []float64{0.5}),
+		mat.NewVecDense(1, []float64{1.0})}
+	y := []float64{1.0, 0.0, 1.0}
+	g := newGP(RBF, 1)
+	fit(g, x, y, true)
+	mu, sigma := predict(g, mat.NewVecDense(1, []float64{0.5}))
+	t.Log(mu, sigma)
+	if math.Abs(mu) > 0.1 {
+		t.Fatal("TestGP fails")
+	}
+}
+
+func TestGPSingular(t *testing.T) {
+	x := []mat.Vector{
+		mat.NewVecDense(1, []float64{0.5}),
+		mat.NewVecDense(1, []float64{0.5}),
+		mat.NewVecDense(1, []float64{0.5})}
+	y := []float64{1.0, 0.0, 2.0}
+	g := newGP(RBF, 1)
+	g.hyper[2] = math.Inf(-1)
+	g.noise_max = math.Inf(-1)
+	fit(g, x, y, false)
+	mu, sigma := predict(g, mat.NewVecDense(1, []float64{0.5}))
+	t.Log(g.chol == nil, mu, sigma)
+	if mu != 1.0 || sigma <= 0.0 {
+		t.Fatal("TestGPSingular fails")
+	}
+}
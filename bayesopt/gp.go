@@ -0,0 +1,168 @@
+package bayesopt
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Gaussian process on inputs scaled to [0,1]^n and standardized outputs.
+// The hyperparameters are log length scales (one per dimension), log signal variance and log noise variance.
+type gp = struct {
+	kernel Kernel
+	x      []mat.Vector
+	y      []float64
+	mean   float64
+	std    float64
+	hyper  []float64
+	chol   *mat.Cholesky
+	alpha  *mat.VecDense
+	// log noise variance of a second factorization if the first one fails
+	noise_max float64
+}
+
+var (
+	log_length_min = math.Log(1e-3)
+	log_length_max = math.Log(1e2)
+	log_noise_min  = math.Log(1e-8)
+	log_noise_max  = math.Log(1e-1)
+	log_signal_min = math.Log(1e-2)
+	log_signal_max = math.Log(1e2)
+)
+
+func newGP(kernel Kernel, n int) *gp {
+	hyper := make([]float64, n+2)
+	for i := 0; i < n; i++ {
+		hyper[i] = math.Log(0.5)
+	}
+	hyper[n] = 0.0
+	hyper[n+1] = math.Log(1e-4)
+	return &gp{kernel: kernel, hyper: hyper, noise_max: log_noise_max}
+}
+
+// fit sets the data and, if requested, re-estimates the hyperparameters by maximizing the marginal likelihood
+func fit(g *gp, x []mat.Vector, y []float64, optimizeHyper bool) {
+	g.x = x
+	g.mean, g.std = standardize(y)
+	g.y = make([]float64, len(y))
+	for i, e := range y {
+		g.y[i] = (e - g.mean) / g.std
+	}
+	if optimizeHyper {
+		problem := optimize.Problem{
+			Func: func(h []float64) float64 {
+				return -logMarginalLikelihood(g, clampHyper(h))
+			}}
+		res, _ := optimize.Minimize(problem, g.hyper,
+			&optimize.Settings{FuncEvaluations: 200 * len(g.hyper)}, &optimize.NelderMead{})
+		if res != nil {
+			candidate := clampHyper(res.X)
+			if logMarginalLikelihood(g, candidate) > logMarginalLikelihood(g, g.hyper) {
+				g.hyper = candidate
+			}
+		}
+	}
+	g.chol, g.alpha = factorize(g, g.hyper)
+	if g.chol == nil {
+		// ill-conditioned, e.g. by repeated points, hence treat the data as more noisy
+		g.hyper[len(g.hyper)-1] = g.noise_max
+		g.chol, g.alpha = factorize(g, g.hyper)
+	}
+}
+
+// predict returns the posterior mean and standard deviation at x,
+// the prior ones if the covariance matrix could not be factorized even with the maximal noise
+func predict(g *gp, x mat.Vector) (float64, float64) {
+	if g.chol == nil {
+		return g.mean, math.Sqrt(covariance(g.kernel, g.hyper, x, x)) * g.std
+	}
+	n := len(g.x)
+	k := mat.NewVecDense(n, nil)
+	for i, x_i := range g.x {
+		k.SetVec(i, covariance(g.kernel, g.hyper, x, x_i))
+	}
+	mu := mat.Dot(k, g.alpha)
+	w := mat.NewVecDense(n, nil)
+	if errSolve := g.chol.SolveVecTo(w, k); errSolve != nil {
+		return mu*g.std + g.mean, 0.0
+	}
+	variance := covariance(g.kernel, g.hyper, x, x) - mat.Dot(k, w)
+	return mu*g.std + g.mean, math.Sqrt(math.Max(variance, 0.0)) * g.std
+}
+
+func logMarginalLikelihood(g *gp, hyper []float64) float64 {
+	chol, alpha := factorize(g, hyper)
+	if chol == nil {
+		return math.Inf(-1)
+	}
+	y := mat.NewVecDense(len(g.y), g.y)
+	return -0.5*mat.Dot(y, alpha) - 0.5*chol.LogDet() - 0.5*float64(len(g.y))*math.Log(2.0*math.Pi)
+}
+
+func factorize(g *gp, hyper []float64) (*mat.Cholesky, *mat.VecDense) {
+	n := len(g.x)
+	noise := math.Exp(hyper[len(hyper)-1])
+	K := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			K.SetSym(i, j, covariance(g.kernel, hyper, g.x[i], g.x[j]))
+		}
+		K.SetSym(i, i, K.At(i, i)+noise)
+	}
+	chol := &mat.Cholesky{}
+	if ok := chol.Factorize(K); !ok {
+		return nil, nil
+	}
+	alpha := mat.NewVecDense(n, nil)
+	if errSolve := chol.SolveVecTo(alpha, mat.NewVecDense(n, g.y)); errSolve != nil {
+		return nil, nil
+	}
+	return chol, alpha
+}
+
+func covariance(kernel Kernel, hyper []float64, a mat.Vector, b mat.Vector) float64 {
+	n := a.Len()
+	r2 := 0.0
+	for i := 0; i < n; i++ {
+		r2 = r2 + math.Pow((a.AtVec(i)-b.AtVec(i))/math.Exp(hyper[i]), 2.0)
+	}
+	signal := math.Exp(hyper[n])
+	r := math.Sqrt(r2)
+	switch kernel {
+	case Matern32:
+		return signal * (1.0 + math.Sqrt(3.0)*r) * math.Exp(-math.Sqrt(3.0)*r)
+	case Matern52:
+		return signal * (1.0 + math.Sqrt(5.0)*r + 5.0*r2/3.0) * math.Exp(-math.Sqrt(5.0)*r)
+	default:
+		return signal * math.Exp(-0.5*r2)
+	}
+}
+
+func clampHyper(hyper []float64) []float64 {
+	n := len(hyper) - 2
+	res := make([]float64, len(hyper))
+	for i := 0; i < n; i++ {
+		res[i] = math.Min(math.Max(hyper[i], log_length_min), log_length_max)
+	}
+	res[n] = math.Min(math.Max(hyper[n], log_signal_min), log_signal_max)
+	res[n+1] = math.Min(math.Max(hyper[n+1], log_noise_min), log_noise_max)
+	return res
+}
+
+func standardize(y []float64) (float64, float64) {
+	mean := 0.0
+	for _, e := range y {
+		mean = mean + e
+	}
+	mean = mean / float64(len(y))
+	variance := 0.0
+	for _, e := range y {
+		variance = variance + math.Pow(e-mean, 2.0)
+	}
+	std := math.Sqrt(variance / float64(len(y)))
+	if std < 1e-12 {
+		std = 1.0
+	}
+	return mean, std
+}
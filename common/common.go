@@ -3,6 +3,8 @@ package common
 import (
 	"math"
 	"math/rand"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"gonum.org/v1/gonum/mat"
@@ -37,9 +39,9 @@ func RandomDataInBounds(b_low mat.Vector, b_up mat.Vector) *mat.VecDense {
 	return v
 }
 
+// GetNextSeed is safe for concurrent use.
 func GetNextSeed() int64 {
-	seed++
-	return seed
+	return atomic.AddInt64(&seed, 1)
 }
 
 func GetRandomSource() int64 {
@@ -49,3 +51,65 @@ func GetRandomSource() int64 {
 func GetNewRand() *rand.Rand {
 	return rand.New(rand.NewSource(GetRandomSource()))
 }
+
+// Opposite returns b_low + b_up - x, the opposite point of opposition-based learning (Tizhoosh 2005).
+func Opposite(x mat.Vector, b_low mat.Vector, b_up mat.Vector) *mat.VecDense {
+	v := mat.NewVecDense(x.Len(), nil)
+	v.AddVec(b_low, b_up)
+	v.SubVec(v, x)
+	return v
+}
+
+// DynamicBounds returns the componentwise minimum and maximum of the points.
+func DynamicBounds(points []mat.Vector) (*mat.VecDense, *mat.VecDense) {
+	n := points[0].Len()
+	b_low := mat.NewVecDense(n, nil)
+	b_up := mat.NewVecDense(n, nil)
+	b_low.CopyVec(points[0])
+	b_up.CopyVec(points[0])
+	for _, x := range points[1:] {
+		for i := 0; i < n; i++ {
+			b_low.SetVec(i, math.Min(b_low.AtVec(i), x.AtVec(i)))
+			b_up.SetVec(i, math.Max(b_up.AtVec(i), x.AtVec(i)))
+		}
+	}
+	return b_low, b_up
+}
+
+// JumpingRate returns the probability of a generation jump of opposition-based learning,
+// rate if it is positive and 0.3 (Rahnamayan, Tizhoosh, Salama 2008) otherwise.
+func JumpingRate(rate float64) float64 {
+	if rate > 0.0 {
+		return rate
+	}
+	return 0.3
+}
+
+// Fitness maps a value to be minimized onto a positive fitness, 1/(1+value) or 1+|value| for negative values (Karaboga).
+func Fitness(value float64) float64 {
+	if value >= 0.0 {
+		return 1.0 / (1.0 + value)
+	}
+	return 1.0 + math.Abs(value)
+}
+
+// SelectBest returns the indices of the n smallest values in ascending order of the values.
+func SelectBest(values []float64, n int) []int {
+	order := Indices(len(values))
+	sort.SliceStable(order, func(a, b int) bool {
+		return values[order[a]] < values[order[b]]
+	})
+	if n > len(order) {
+		n = len(order)
+	}
+	return order[:n]
+}
+
+// Indices returns 0, ..., n-1.
+func Indices(n int) []int {
+	res := make([]int, n)
+	for i := range res {
+		res[i] = i
+	}
+	return res
+}
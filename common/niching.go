@@ -0,0 +1,75 @@
+package common
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Optimum is one of several optima located by a niching method.
+type Optimum = struct {
+	X     mat.Vector
+	Value float64
+}
+
+// Distance returns the Euclidean distance of a and b.
+func Distance(a mat.Vector, b mat.Vector) float64 {
+	d := mat.NewVecDense(a.Len(), nil)
+	d.SubVec(a, b)
+	return mat.Norm(d, 2)
+}
+
+// DetermineSpecies returns the species seeds sorted by value and for each point the seed of its species.
+// Going through the points by value, a point not closer than radius to an existing seed becomes a seed (Li 2004).
+func DetermineSpecies(points []mat.Vector, values []float64, radius float64) ([]int, []int) {
+	seeds := make([]int, 0)
+	species := make([]int, len(points))
+	for _, i := range SelectBest(values, len(values)) {
+		species[i] = i
+		for _, s := range seeds {
+			if Distance(points[i], points[s]) <= radius {
+				species[i] = s
+				break
+			}
+		}
+		if species[i] == i {
+			seeds = append(seeds, i)
+		}
+	}
+	return seeds, species
+}
+
+// SurplusMembers returns the worst members of the species having more than species_size members.
+// They are removed from their species, i.e. species[i] is set to i.
+func SurplusMembers(seeds []int, species []int, values []float64, species_size int) []int {
+	res := make([]int, 0)
+	for _, s := range seeds {
+		members := make([]int, 0)
+		for i, e := range species {
+			if e == s {
+				members = append(members, i)
+			}
+		}
+		if len(members) <= species_size {
+			continue
+		}
+		sort.SliceStable(members, func(a, b int) bool {
+			return values[members[a]] < values[members[b]]
+		})
+		for _, i := range members[species_size:] {
+			species[i] = i
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// SpeciesSeeds returns the seeds of DetermineSpecies as optima sorted by value.
+func SpeciesSeeds(points []mat.Vector, values []float64, radius float64) []Optimum {
+	seeds, _ := DetermineSpecies(points, values, radius)
+	res := make([]Optimum, len(seeds))
+	for k, s := range seeds {
+		res[k] = Optimum{X: points[s], Value: values[s]}
+	}
+	return res
+}
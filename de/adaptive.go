@@ -0,0 +1,249 @@
+package de
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Adaptation selects how F and CR are chosen. All adaptive variants use current-to-pbest/1 mutation
+// with F and CR sampled per agent around values learned from successful trials.
+type Adaptation int
+
+const (
+	Fixed  Adaptation = iota // F and CR of Params
+	JADE                     // Zhang, Sanderson 2009
+	SHADE                    // success-history memory, Tanabe, Fukunaga 2013
+	LSHADE                   // SHADE with linear population-size reduction, Tanabe, Fukunaga 2014
+)
+
+// Trajectory holds per iteration the adapted location of F and CR (mu of JADE, mean of the memory of SHADE)
+// and the population size.
+type Trajectory = struct {
+	F        []float64
+	CR       []float64
+	N_agents []int
+}
+
+type memory = struct {
+	F  []float64
+	CR []float64 // negative means CR is fixed to 0 (terminal value of LSHADE)
+	k  int
+}
+
+// OptimizeAdaptive runs the variant given by params.Adaptation and returns the best agent
+// together with the trajectory of the adapted parameters.
+func OptimizeAdaptive(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) (mat.Vector, *Trajectory) {
+	r := common.GetNewRand()
+	agents := initAgents(b_low, b_up, params.N_agents, params.Init)
+	values := evaluateAgents(f, agents)
+	if params.Opposition {
+		agents, values = opposeAgents(f, agents, values, b_low, b_up)
+	}
+	archive := make([]mat.Vector, 0)
+	m := initMemory(params)
+	trajectory := &Trajectory{}
+	for iter := 0; iter <= params.Max_iter; iter++ {
+		best := findBest(values)
+		ranked := common.SelectBest(values, len(values))
+		var s_F, s_CR, improvements []float64
+		next_agents := append([]mat.Vector(nil), agents...)
+		next_values := append([]float64(nil), values...)
+		for i, x := range agents {
+			F, CR, p := sampleParameters(m, len(agents), params, r)
+			var a []mat.Vector
+			if params.Adaptation != JADE || params.Archive {
+				a = archive
+			}
+			donor := mutate(CurrentToPBest1, agents, ranked, i, best, F, p, a, r)
+			repairBounds(donor, x, b_low, b_up)
+			y := crossover(params.Crossover, x, donor, CR, r)
+			y_val := f(y)
+			if y_val <= values[i] {
+				if y_val < values[i] {
+					archive = append(archive, x)
+					s_F = append(s_F, F)
+					s_CR = append(s_CR, CR)
+					improvements = append(improvements, values[i]-y_val)
+				}
+				next_agents[i] = y
+				next_values[i] = y_val
+			}
+		}
+		agents, values = jumpGeneration(f, next_agents, next_values, params, r)
+		updateMemory(m, s_F, s_CR, improvements, params)
+		if params.Adaptation == LSHADE {
+			agents, values = reducePopulation(agents, values, iter, params)
+		}
+		archive = trimArchive(archive, int(math.Round(archiveRate(params)*float64(len(agents)))), r)
+		trajectory.F = append(trajectory.F, mean(m.F))
+		trajectory.CR = append(trajectory.CR, meanCR(m))
+		trajectory.N_agents = append(trajectory.N_agents, len(agents))
+	}
+	return agents[findBest(values)], trajectory
+}
+
+func initMemory(params *Params) *memory {
+	h := 1
+	if params.Adaptation != JADE {
+		h = params.Memory_size
+		if h <= 0 && params.Adaptation == LSHADE {
+			h = 6
+		}
+		if h <= 0 {
+			h = params.N_agents
+		}
+	}
+	m := &memory{F: make([]float64, h), CR: make([]float64, h)}
+	for i := 0; i < h; i++ {
+		m.F[i] = 0.5
+		m.CR[i] = 0.5
+	}
+	return m
+}
+
+// CR is normal and F Cauchy distributed around a memory entry, p is the greediness of current-to-pbest
+func sampleParameters(m *memory, n_agents int, params *Params, r *rand.Rand) (float64, float64, float64) {
+	k := r.Intn(len(m.F))
+	CR := 0.0
+	if m.CR[k] >= 0.0 {
+		CR = math.Min(math.Max(m.CR[k]+0.1*r.NormFloat64(), 0.0), 1.0)
+	}
+	F := 0.0
+	for F <= 0.0 {
+		F = m.F[k] + 0.1*math.Tan(math.Pi*(r.Float64()-0.5))
+	}
+	F = math.Min(F, 1.0)
+	p := params.P
+	if p <= 0.0 && params.Adaptation == JADE {
+		p = 0.05
+	}
+	if p <= 0.0 {
+		p_min := 2.0 / float64(n_agents)
+		p = p_min + r.Float64()*math.Max(0.2-p_min, 0.0)
+	}
+	return F, CR, p
+}
+
+func updateMemory(m *memory, s_F []float64, s_CR []float64, improvements []float64, params *Params) {
+	if len(s_F) == 0 {
+		return
+	}
+	if params.Adaptation == JADE {
+		c := params.C
+		if c <= 0.0 {
+			c = 0.1
+		}
+		m.CR[0] = (1.0-c)*m.CR[0] + c*mean(s_CR)
+		m.F[0] = (1.0-c)*m.F[0] + c*lehmerMean(s_F, nil)
+		return
+	}
+	if params.Adaptation == LSHADE {
+		if m.CR[m.k] < 0.0 || maxOf(s_CR) == 0.0 {
+			m.CR[m.k] = -1.0
+		} else {
+			m.CR[m.k] = lehmerMean(s_CR, improvements)
+		}
+	} else {
+		m.CR[m.k] = weightedMean(s_CR, improvements)
+	}
+	m.F[m.k] = lehmerMean(s_F, improvements)
+	m.k = (m.k + 1) % len(m.F)
+}
+
+// the population shrinks linearly from N_agents to N_min by dropping the worst agents
+func reducePopulation(agents []mat.Vector, values []float64, iter int, params *Params) ([]mat.Vector, []float64) {
+	n_min := params.N_min
+	if n_min <= 0 {
+		n_min = 4
+	}
+	progress := float64(iter+1) / math.Max(float64(params.Max_iter+1), 1.0)
+	target := int(math.Round(float64(params.N_agents) + (float64(n_min)-float64(params.N_agents))*progress))
+	for len(agents) > target && len(agents) > n_min {
+		worst := 0
+		for i, v := range values {
+			if v > values[worst] {
+				worst = i
+			}
+		}
+		agents = append(agents[:worst], agents[worst+1:]...)
+		values = append(values[:worst], values[worst+1:]...)
+	}
+	return agents, values
+}
+
+func trimArchive(archive []mat.Vector, size int, r *rand.Rand) []mat.Vector {
+	for len(archive) > size {
+		k := r.Intn(len(archive))
+		archive[k] = archive[len(archive)-1]
+		archive = archive[:len(archive)-1]
+	}
+	return archive
+}
+
+func archiveRate(params *Params) float64 {
+	if params.Archive_rate > 0.0 {
+		return params.Archive_rate
+	}
+	if params.Adaptation == LSHADE {
+		return 2.6
+	}
+	return 1.0
+}
+
+// sum w x^2 / sum w x, unweighted if w is nil
+func lehmerMean(x []float64, w []float64) float64 {
+	num, den := 0.0, 0.0
+	for i, e := range x {
+		weight := 1.0
+		if w != nil {
+			weight = w[i]
+		}
+		num = num + weight*e*e
+		den = den + weight*e
+	}
+	if den == 0.0 {
+		return 0.0
+	}
+	return num / den
+}
+
+func weightedMean(x []float64, w []float64) float64 {
+	num, den := 0.0, 0.0
+	for i, e := range x {
+		num = num + w[i]*e
+		den = den + w[i]
+	}
+	if den == 0.0 {
+		return mean(x)
+	}
+	return num / den
+}
+
+// terminal values count as 0
+func meanCR(m *memory) float64 {
+	res := 0.0
+	for _, e := range m.CR {
+		res = res + math.Max(e, 0.0)
+	}
+	return res / float64(len(m.CR))
+}
+
+func mean(x []float64) float64 {
+	res := 0.0
+	for _, e := range x {
+		res = res + e
+	}
+	return res / float64(len(x))
+}
+
+func maxOf(x []float64) float64 {
+	res := math.Inf(-1)
+	for _, e := range x {
+		res = math.Max(res, e)
+	}
+	return res
+}
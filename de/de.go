@@ -1,69 +1,238 @@
 package de
 
 import (
+	"math"
+	"math/rand"
+
 	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/sampling"
 
 	"gonum.org/v1/gonum/mat"
 )
 
+// Strategy is the mutation scheme DE/x/y, the crossover is chosen separately.
+type Strategy int
+
+const (
+	Rand1           Strategy = iota // a + F (b - c)
+	Best1                           // best + F (a - b)
+	CurrentToBest1                  // x + F (best - x) + F (a - b)
+	Rand2                           // a + F (b - c) + F (d - e)
+	Best2                           // best + F (a - b) + F (c - d)
+	CurrentToPBest1                 // x + F (pbest - x) + F (a - b), pbest is one of the P * N_agents best
+)
+
+type Crossover int
+
+const (
+	Binomial    Crossover = iota // each component is taken from the mutant with probability CR
+	Exponential                  // a consecutive block of components is taken from the mutant
+)
+
 type Params = struct {
-	N_agents int
-	F        float64
-	CR       float64
-	Max_iter int
+	N_agents  int
+	F         float64
+	CR        float64
+	Max_iter  int
+	Strategy  Strategy
+	Crossover Crossover
+	P         float64 // CurrentToPBest1, fraction of best agents, defaults to 0.05 (random in [2/N, 0.2] for SHADE)
+	// adaptive variants, see OptimizeAdaptive
+	Adaptation   Adaptation
+	C            float64 // JADE, learning rate of the adapted F and CR, defaults to 0.1
+	Archive      bool    // JADE, keep replaced parents as donors, SHADE and LSHADE always do
+	Archive_rate float64 // archive size relative to the population, defaults to 1 (2.6 for LSHADE)
+	Memory_size  int     // SHADE and LSHADE, defaults to N_agents (6 for LSHADE)
+	N_min        int     // LSHADE, final population size, defaults to 4
+	// multimodal variants, see FindOptima
+	Niching            Niching
+	Niche_radius       float64 // distance below which agents belong to the same optimum
+	Species_size       int     // Speciation, surplus members of a species are re-initialised randomly (0 means unlimited)
+	Neighbourhood_size int     // NeighbourhoodMutation, number of nearest agents providing donors, defaults to 5
+	// opposition-based learning of Optimize and OptimizeAdaptive
+	Opposition   bool             // the initial population and generation jumps keep the better half of agents and their opposites
+	Jumping_rate float64          // probability of a generation jump per iteration, defaults to 0.3
+	Init         sampling.Sampler // initial agents, nil means uniform
 }
 
 func Optimize(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
-	agents := initAgents(b_low, b_up, params.N_agents)
-	n := b_low.Len()
-	global_best := findBest(f, agents)
-	global_best_val := f(global_best)
+	if params.Adaptation != Fixed {
+		best, _ := OptimizeAdaptive(f, b_low, b_up, params)
+		return best
+	}
+	agents := initAgents(b_low, b_up, params.N_agents, params.Init)
+	values := evaluateAgents(f, agents)
+	if params.Opposition {
+		agents, values = opposeAgents(f, agents, values, b_low, b_up)
+	}
+	best := findBest(values)
 	for iter := 0; iter <= params.Max_iter; iter++ {
 		rand := common.GetNewRand()
+		ranked := common.SelectBest(values, len(values))
 		for agentIdx, x := range agents {
-			a := agents[rand.Intn(n)]
-			b := agents[rand.Intn(n)]
-			c := agents[rand.Intn(n)]
-			R := rand.Intn(n)
-			y := mat.NewVecDense(n, nil)
-			for i := 0; i < n; i++ {
-				r := rand.Float64()
-				if r < params.CR || i == R {
-					y.SetVec(i, a.AtVec(i)+params.F*(b.AtVec(i)-c.AtVec(i)))
-				} else {
-					y.SetVec(i, x.AtVec(i))
-				}
-			}
+			donor := mutate(params.Strategy, agents, ranked, agentIdx, best, params.F, params.P, nil, rand)
+			repairBounds(donor, x, b_low, b_up)
+			y := crossover(params.Crossover, x, donor, params.CR, rand)
 			y_val := f(y)
-			if y_val < f(x) {
+			if y_val < values[agentIdx] {
 				agents[agentIdx] = y
-				if y_val < global_best_val {
-					global_best = y
-					global_best_val = y_val
+				values[agentIdx] = y_val
+				if y_val < values[best] {
+					best = agentIdx
 				}
 			}
 		}
+		agents, values = jumpGeneration(f, agents, values, params, rand)
+		best = findBest(values)
+	}
+	return agents[best]
+}
+
+// mutate creates the donor vector for agent i. The random agents are distinct from each other and from i.
+// ranked holds the agent indices sorted by value, it is only used by CurrentToPBest1.
+// If an archive is given, the last random vector of CurrentToPBest1 is drawn from the agents and the archive.
+func mutate(strategy Strategy, agents []mat.Vector, ranked []int, i int, best int,
+	F float64, p float64, archive []mat.Vector, rand *rand.Rand) *mat.VecDense {
+	n := agents[i].Len()
+	y := mat.NewVecDense(n, nil)
+	switch strategy {
+	case Best1:
+		idx := sampleDistinct(len(agents), 2, rand, i, best)
+		y.CopyVec(agents[best])
+		addDifference(y, F, agents[idx[0]], agents[idx[1]])
+	case CurrentToBest1:
+		idx := sampleDistinct(len(agents), 2, rand, i, best)
+		y.CopyVec(agents[i])
+		addDifference(y, F, agents[best], agents[i])
+		addDifference(y, F, agents[idx[0]], agents[idx[1]])
+	case Rand2:
+		idx := sampleDistinct(len(agents), 5, rand, i)
+		y.CopyVec(agents[idx[0]])
+		addDifference(y, F, agents[idx[1]], agents[idx[2]])
+		addDifference(y, F, agents[idx[3]], agents[idx[4]])
+	case Best2:
+		idx := sampleDistinct(len(agents), 4, rand, i, best)
+		y.CopyVec(agents[best])
+		addDifference(y, F, agents[idx[0]], agents[idx[1]])
+		addDifference(y, F, agents[idx[2]], agents[idx[3]])
+	case CurrentToPBest1:
+		pbest := choosePBest(ranked, p, rand)
+		a := sampleDistinct(len(agents), 1, rand, i, pbest)[0]
+		b := sampleDistinct(len(agents)+len(archive), 1, rand, i, pbest, a)[0]
+		y.CopyVec(agents[i])
+		addDifference(y, F, agents[pbest], agents[i])
+		if b < len(agents) {
+			addDifference(y, F, agents[a], agents[b])
+		} else {
+			addDifference(y, F, agents[a], archive[b-len(agents)])
+		}
+	default:
+		idx := sampleDistinct(len(agents), 3, rand, i)
+		y.CopyVec(agents[idx[0]])
+		addDifference(y, F, agents[idx[1]], agents[idx[2]])
+	}
+	return y
+}
+
+// crossover mixes the target x with the donor, at least one component is taken from the donor
+func crossover(crossover Crossover, x mat.Vector, donor *mat.VecDense, CR float64, rand *rand.Rand) *mat.VecDense {
+	n := x.Len()
+	y := mat.NewVecDense(n, nil)
+	y.CopyVec(x)
+	R := rand.Intn(n)
+	if crossover == Exponential {
+		for L := 0; L < n; L++ {
+			idx := (R + L) % n
+			y.SetVec(idx, donor.AtVec(idx))
+			if rand.Float64() >= CR {
+				break
+			}
+		}
+		return y
+	}
+	for i := 0; i < n; i++ {
+		if rand.Float64() < CR || i == R {
+			y.SetVec(i, donor.AtVec(i))
+		}
 	}
-	return global_best
+	return y
+}
+
+// components leaving the box are set halfway between the bound and the parent
+func repairBounds(y *mat.VecDense, parent mat.Vector, b_low mat.Vector, b_up mat.Vector) {
+	common.ApplyElementwise(y, func(e float64, idx int) float64 {
+		if e < b_low.AtVec(idx) {
+			return (b_low.AtVec(idx) + parent.AtVec(idx)) / 2.0
+		}
+		if e > b_up.AtVec(idx) {
+			return (b_up.AtVec(idx) + parent.AtVec(idx)) / 2.0
+		}
+		return e
+	})
+}
+
+// y = y + F (a - b)
+func addDifference(y *mat.VecDense, F float64, a mat.Vector, b mat.Vector) {
+	diff := mat.NewVecDense(y.Len(), nil)
+	diff.SubVec(a, b)
+	y.AddScaledVec(y, F, diff)
+}
+
+// sampleDistinct draws k distinct indices from [0, n) which are not contained in exclude.
+// Exclusions are dropped if too few indices would remain, and repetitions are allowed if n < k.
+func sampleDistinct(n int, k int, rand *rand.Rand, exclude ...int) []int {
+	excluded := make(map[int]bool)
+	for _, e := range exclude {
+		if n-len(excluded) > k {
+			excluded[e] = true
+		}
+	}
+	distinct := n >= k
+	res := make([]int, 0, k)
+	for len(res) < k {
+		idx := rand.Intn(n)
+		if excluded[idx] {
+			continue
+		}
+		if distinct {
+			excluded[idx] = true
+		}
+		res = append(res, idx)
+	}
+	return res
+}
+
+// one of the p * len(ranked) best agents
+func choosePBest(ranked []int, p float64, rand *rand.Rand) int {
+	if p <= 0.0 {
+		p = 0.05
+	}
+	top := int(math.Max(1.0, math.Round(p*float64(len(ranked)))))
+	return ranked[rand.Intn(top)]
+}
+
+func evaluateAgents(f common.Target, agents []mat.Vector) []float64 {
+	res := make([]float64, len(agents))
+	for i, x := range agents {
+		res[i] = f(x)
+	}
+	return res
 }
 
-func findBest(f common.Target, agents []mat.Vector) mat.Vector {
-	best_agent := agents[0]
-	best_val := f(best_agent)
-	for _, x := range agents {
-		x_val := f(x)
-		if x_val < best_val {
-			best_agent = x
-			best_val = x_val
+func findBest(values []float64) int {
+	best := 0
+	for i, v := range values {
+		if v < values[best] {
+			best = i
 		}
 	}
-	return best_agent
+	return best
 }
 
-func initAgents(b_low mat.Vector, b_up mat.Vector, n_agents int) []mat.Vector {
+func initAgents(b_low mat.Vector, b_up mat.Vector, n_agents int, init sampling.Sampler) []mat.Vector {
 	res := make([]mat.Vector, n_agents)
-	for i := 0; i < n_agents; i++ {
-		res[i] = common.RandomDataInBounds(b_low, b_up)
+	for i, x := range sampling.Sample(init, b_low, b_up, n_agents) {
+		res[i] = x
 	}
 	return res
 }
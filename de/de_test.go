@@ -4,6 +4,8 @@ import (
 	"math"
 	"testing"
 
+	"github.com/applied-math-coding/heuristic/common"
+
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -24,3 +26,156 @@ func TestDe(t *testing.T) {
 	t.Log(min)
 	t.Log(f(min))
 }
+
+func TestDeStrategies(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	strategies := []Strategy{Rand1, Best1, CurrentToBest1, Rand2, Best2, CurrentToPBest1}
+	for _, strategy := range strategies {
+		for _, crossover := range []Crossover{Binomial, Exponential} {
+			params := &Params{
+				N_agents:  20,
+				Max_iter:  50,
+				F:         0.5,
+				CR:        0.9,
+				Strategy:  strategy,
+				Crossover: crossover,
+				P:         0.1}
+			min := Optimize(f, b_low, b_up, params)
+			t.Log(strategy, crossover, min, f(min))
+		}
+	}
+}
+
+func TestDeBounds(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{0.0, 0.0})
+	b_up := mat.NewVecDense(2, []float64{1.0, 1.0})
+	outside := 0
+	// the minimum lies in a corner, so mutants leave the box frequently
+	f := func(x mat.Vector) float64 {
+		for i := 0; i < x.Len(); i++ {
+			if x.AtVec(i) < b_low.AtVec(i) || x.AtVec(i) > b_up.AtVec(i) {
+				outside++
+			}
+		}
+		return -x.AtVec(0) - x.AtVec(1)
+	}
+	for _, strategy := range []Strategy{Rand1, Best1, CurrentToBest1, Rand2, Best2, CurrentToPBest1} {
+		Optimize(f, b_low, b_up, &Params{N_agents: 20, Max_iter: 50, F: 0.9, CR: 0.9, Strategy: strategy})
+	}
+	for _, niching := range []Niching{Crowding, Speciation, NeighbourhoodMutation} {
+		FindOptima(f, b_low, b_up, &Params{N_agents: 20, Max_iter: 50, F: 0.9, CR: 0.9, Niching: niching,
+			Niche_radius: 0.1})
+	}
+	if outside > 0 {
+		t.Fatal("evaluations outside the bounds:", outside)
+	}
+}
+
+func TestDeOpposition(t *testing.T) {
+	n := 10
+	b_low := mat.NewVecDense(n, nil)
+	b_up := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		b_low.SetVec(i, -5.0)
+		b_up.SetVec(i, 10.0)
+	}
+	f := func(x mat.Vector) float64 {
+		res := 0.0
+		for i := 0; i < x.Len(); i++ {
+			res = res + math.Pow(x.AtVec(i)-7.0, 2.0)
+		}
+		return res
+	}
+	for _, opposition := range []bool{false, true} {
+		params := &Params{
+			N_agents:   20,
+			Max_iter:   100,
+			F:          0.5,
+			CR:         0.9,
+			Opposition: opposition}
+		min := Optimize(f, b_low, b_up, params)
+		t.Log(opposition, f(min))
+		params.Adaptation = SHADE
+		min = Optimize(f, b_low, b_up, params)
+		t.Log(opposition, "SHADE", f(min))
+	}
+}
+
+func TestSampleDistinct(t *testing.T) {
+	r := common.GetNewRand()
+	for k := 0; k < 100; k++ {
+		idx := sampleDistinct(6, 5, r, 0)
+		seen := make(map[int]bool)
+		for _, i := range idx {
+			if i == 0 || seen[i] {
+				t.Fatal("TestSampleDistinct fails", idx)
+			}
+			seen[i] = true
+		}
+	}
+	t.Log(sampleDistinct(3, 5, r, 0))
+}
+
+func TestDeAdaptive(t *testing.T) {
+	n := 10
+	b_low := mat.NewVecDense(n, nil)
+	b_up := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		b_low.SetVec(i, -5.12)
+		b_up.SetVec(i, 5.12)
+	}
+	rastrigin := func(x mat.Vector) float64 {
+		res := 10.0 * float64(x.Len())
+		for i := 0; i < x.Len(); i++ {
+			res = res + math.Pow(x.AtVec(i), 2.0) - 10.0*math.Cos(2.0*math.Pi*x.AtVec(i))
+		}
+		return res
+	}
+	for _, adaptation := range []Adaptation{JADE, SHADE, LSHADE} {
+		params := &Params{
+			N_agents:   50,
+			Max_iter:   300,
+			Adaptation: adaptation,
+			Archive:    true}
+		min, trajectory := OptimizeAdaptive(rastrigin, b_low, b_up, params)
+		last := len(trajectory.F) - 1
+		t.Log(adaptation, rastrigin(min), trajectory.F[last], trajectory.CR[last], trajectory.N_agents[last])
+	}
+}
+
+func TestFindOptima(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-6.0, -6.0})
+	b_up := mat.NewVecDense(2, []float64{6.0, 6.0})
+	himmelblau := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow(x0*x0+x1-11.0, 2.0) + math.Pow(x0+x1*x1-7.0, 2.0)
+	}
+	for _, niching := range []Niching{Crowding, Speciation, NeighbourhoodMutation} {
+		params := &Params{
+			N_agents:     100,
+			Max_iter:     200,
+			F:            0.5,
+			CR:           0.9,
+			Niching:      niching,
+			Niche_radius: 2.0,
+			Species_size: 20}
+		optima := FindOptima(himmelblau, b_low, b_up, params)
+		found := 0
+		for _, o := range optima {
+			if o.Value < 0.1 {
+				found++
+				t.Log(niching, o.X, o.Value)
+			}
+		}
+		if found < 3 {
+			t.Fatal(niching, "found only", found, "of 4 optima")
+		}
+	}
+}
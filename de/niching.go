@@ -0,0 +1,130 @@
+package de
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Niching selects how FindOptima keeps several optima alive.
+type Niching int
+
+const (
+	Crowding              Niching = iota // the trial replaces its nearest agent if better (Thomsen 2004)
+	Speciation                           // donors are taken from the species of the agent (Li 2005)
+	NeighbourhoodMutation                // donors are taken from the Neighbourhood_size nearest agents, crowding replacement (Qu, Suganthan, Liang 2012)
+)
+
+type Optimum = common.Optimum
+
+// FindOptima runs a niching DE in order to locate several local optima in one run.
+// Mutation and crossover follow Strategy and Crossover, but the donors are restricted according to Niching.
+// The returned optima are the best agents not closer than Niche_radius to a better one, sorted by value.
+func FindOptima(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) []Optimum {
+	r := common.GetNewRand()
+	agents := initAgents(b_low, b_up, params.N_agents, params.Init)
+	values := evaluateAgents(f, agents)
+	for iter := 0; iter <= params.Max_iter; iter++ {
+		switch params.Niching {
+		case Speciation:
+			seeds, species := common.DetermineSpecies(agents, values, params.Niche_radius)
+			if params.Species_size > 0 {
+				// surplus members are re-initialised randomly
+				for _, i := range common.SurplusMembers(seeds, species, values, params.Species_size) {
+					agents[i] = common.RandomDataInBounds(b_low, b_up)
+					values[i] = f(agents[i])
+				}
+			}
+			for i, x := range agents {
+				members := []int{i}
+				for j, s := range species {
+					if s == species[i] && j != i {
+						members = append(members, j)
+					}
+				}
+				y := trialWithin(padSpecies(members, agents, x, 4), agents, values, x, b_low, b_up, params, r)
+				if y_val := f(y); y_val < values[i] {
+					agents[i] = y
+					values[i] = y_val
+				}
+			}
+		default:
+			for i, x := range agents {
+				var y *mat.VecDense
+				if params.Niching == NeighbourhoodMutation {
+					y = trialWithin(append([]int{i}, nearestAgents(agents, x, neighbourhoodSize(params)+1)[1:]...),
+						agents, values, x, b_low, b_up, params, r)
+				} else {
+					y = trialWithin(common.Indices(len(agents)), agents, values, x, b_low, b_up, params, r)
+				}
+				y_val := f(y)
+				if k := nearestAgents(agents, y, 1)[0]; y_val < values[k] {
+					agents[k] = y
+					values[k] = y_val
+				}
+			}
+		}
+	}
+	return common.SpeciesSeeds(agents, values, params.Niche_radius)
+}
+
+// trialWithin creates the trial of x by mutating within the given agents, the first one being x
+func trialWithin(members []int, agents []mat.Vector, values []float64, x mat.Vector, b_low mat.Vector,
+	b_up mat.Vector, params *Params, r *rand.Rand) *mat.VecDense {
+	sub_agents := make([]mat.Vector, len(members))
+	sub_values := make([]float64, len(members))
+	for k, j := range members {
+		sub_agents[k] = agents[j]
+		sub_values[k] = values[j]
+	}
+	donor := mutate(params.Strategy, sub_agents, common.SelectBest(sub_values, len(sub_values)), 0,
+		findBest(sub_values), params.F, params.P, nil, r)
+	repairBounds(donor, x, b_low, b_up)
+	return crossover(params.Crossover, x, donor, params.CR, r)
+}
+
+// small species are filled up with the nearest other agents so that mutation has enough donors
+func padSpecies(members []int, agents []mat.Vector, x mat.Vector, size int) []int {
+	if len(members) >= size {
+		return members
+	}
+	contained := make(map[int]bool)
+	for _, j := range members {
+		contained[j] = true
+	}
+	for _, j := range nearestAgents(agents, x, len(agents)) {
+		if len(members) >= size {
+			break
+		}
+		if !contained[j] {
+			members = append(members, j)
+		}
+	}
+	return members
+}
+
+func neighbourhoodSize(params *Params) int {
+	if params.Neighbourhood_size > 0 {
+		return params.Neighbourhood_size
+	}
+	return 5
+}
+
+// returns the indices of the k agents nearest to y, the nearest first
+func nearestAgents(agents []mat.Vector, y mat.Vector, k int) []int {
+	order := common.Indices(len(agents))
+	d := make([]float64, len(agents))
+	for i, a := range agents {
+		d[i] = common.Distance(a, y)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return d[order[a]] < d[order[b]]
+	})
+	if k > len(order) {
+		k = len(order)
+	}
+	return order[:k]
+}
@@ -0,0 +1,39 @@
+package de
+
+import (
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// opposeAgents evaluates the opposite points of all agents with respect to the given bounds
+// and keeps the best len(agents) of both sets (Rahnamayan, Tizhoosh, Salama 2008).
+func opposeAgents(f common.Target, agents []mat.Vector, values []float64,
+	b_low mat.Vector, b_up mat.Vector) ([]mat.Vector, []float64) {
+	all := append([]mat.Vector(nil), agents...)
+	all_values := append([]float64(nil), values...)
+	for _, x := range agents {
+		y := common.Opposite(x, b_low, b_up)
+		all = append(all, y)
+		all_values = append(all_values, f(y))
+	}
+	res := make([]mat.Vector, len(agents))
+	res_values := make([]float64, len(agents))
+	for k, i := range common.SelectBest(all_values, len(agents)) {
+		res[k] = all[i]
+		res_values[k] = all_values[i]
+	}
+	return res, res_values
+}
+
+// jumpGeneration applies opposeAgents within the dynamic bounds of the population with probability Jumping_rate
+func jumpGeneration(f common.Target, agents []mat.Vector, values []float64, params *Params,
+	r *rand.Rand) ([]mat.Vector, []float64) {
+	if !params.Opposition || r.Float64() >= common.JumpingRate(params.Jumping_rate) {
+		return agents, values
+	}
+	b_low, b_up := common.DynamicBounds(agents)
+	return opposeAgents(f, agents, values, b_low, b_up)
+}
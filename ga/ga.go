@@ -0,0 +1,336 @@
+package ga
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Encoding int
+
+const (
+	Real   Encoding = iota
+	Binary          // each dimension is encoded by Bits bits
+)
+
+type Selection int
+
+const (
+	Tournament Selection = iota
+	Roulette
+	Rank
+)
+
+type Crossover int
+
+const (
+	SBX Crossover = iota
+	BLX_alpha
+	Uniform
+)
+
+type Mutation int
+
+const (
+	Polynomial Mutation = iota
+	Gaussian
+)
+
+type Replacement int
+
+const (
+	Generational Replacement = iota
+	SteadyState              // offspring replace the worst individual if they are better
+)
+
+// Crossover and Mutation only apply to Real encoding. Binary chromosomes use
+// uniform crossover and bit-flip mutation.
+// Zero values of Tournament_size, Eta_c, Eta_m, Alpha, Sigma, Mutation_rate and Bits
+// are replaced by commonly used defaults.
+type Params = struct {
+	N_individuals   int
+	Max_iter        int
+	Encoding        Encoding
+	Bits            int // bits per dimension, defaults to 16
+	Selection       Selection
+	Tournament_size int // defaults to 2
+	Crossover       Crossover
+	Crossover_rate  float64
+	Eta_c           float64 // distribution index of SBX, defaults to 20
+	Alpha           float64 // BLX-alpha, defaults to 0.5
+	Mutation        Mutation
+	Mutation_rate   float64 // per gene, defaults to 1 / chromosome length
+	Eta_m           float64 // distribution index of polynomial mutation, defaults to 20
+	Sigma           float64 // Gaussian mutation relative to b_up - b_low, defaults to 0.1
+	Elitism         int     // best individuals kept by Generational replacement
+	Replacement     Replacement
+}
+
+type IndividualType = struct {
+	genes []float64 // Real encoding
+	bits  []bool    // Binary encoding
+	value float64
+}
+
+type Individual = *IndividualType
+
+func Optimize(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
+	p := withDefaults(params, b_low.Len())
+	r := common.GetNewRand()
+	population := initPopulation(f, b_low, b_up, p)
+	for iter := 0; iter < p.Max_iter; iter++ {
+		if p.Replacement == SteadyState {
+			for k := 0; k < len(population); k += 2 {
+				for _, child := range createOffspring(f, population, b_low, b_up, p, r) {
+					sortPopulation(population)
+					if child.value < population[len(population)-1].value {
+						population[len(population)-1] = child
+					}
+				}
+			}
+		} else {
+			sortPopulation(population)
+			next := make([]Individual, 0, len(population))
+			for i := 0; i < p.Elitism && i < len(population); i++ {
+				next = append(next, population[i])
+			}
+			for len(next) < len(population) {
+				next = append(next, createOffspring(f, population, b_low, b_up, p, r)...)
+			}
+			population = next[:len(population)]
+		}
+	}
+	sortPopulation(population)
+	return decode(population[0], b_low, b_up, p)
+}
+
+func withDefaults(params *Params, n int) *Params {
+	p := *params
+	if p.Bits <= 0 {
+		p.Bits = 16
+	}
+	if p.Tournament_size <= 0 {
+		p.Tournament_size = 2
+	}
+	if p.Eta_c <= 0.0 {
+		p.Eta_c = 20.0
+	}
+	if p.Eta_m <= 0.0 {
+		p.Eta_m = 20.0
+	}
+	if p.Alpha <= 0.0 {
+		p.Alpha = 0.5
+	}
+	if p.Sigma <= 0.0 {
+		p.Sigma = 0.1
+	}
+	if p.Mutation_rate <= 0.0 {
+		length := n
+		if p.Encoding == Binary {
+			length = n * p.Bits
+		}
+		p.Mutation_rate = 1.0 / float64(length)
+	}
+	return &p
+}
+
+func createOffspring(f common.Target, population []Individual, b_low mat.Vector, b_up mat.Vector,
+	params *Params, r *rand.Rand) []Individual {
+	p1 := selectParent(population, params, r)
+	p2 := selectParent(population, params, r)
+	var children []Individual
+	if params.Encoding == Binary {
+		children = crossBits(p1, p2, params, r)
+		for _, c := range children {
+			mutateBits(c, params, r)
+		}
+	} else {
+		children = crossGenes(p1, p2, b_low, b_up, params, r)
+		for _, c := range children {
+			mutateGenes(c, b_low, b_up, params, r)
+		}
+	}
+	for _, c := range children {
+		c.value = f(decode(c, b_low, b_up, params))
+	}
+	return children
+}
+
+func selectParent(population []Individual, params *Params, r *rand.Rand) Individual {
+	switch params.Selection {
+	case Roulette:
+		weights := make([]float64, len(population))
+		for i, ind := range population {
+			weights[i] = common.Fitness(ind.value)
+		}
+		return population[spinWheel(weights, r)]
+	case Rank:
+		sorted := append([]Individual(nil), population...)
+		sortPopulation(sorted)
+		weights := make([]float64, len(sorted))
+		for i := range sorted {
+			weights[i] = float64(len(sorted) - i)
+		}
+		return sorted[spinWheel(weights, r)]
+	default:
+		best := population[r.Intn(len(population))]
+		for k := 1; k < params.Tournament_size; k++ {
+			ind := population[r.Intn(len(population))]
+			if ind.value < best.value {
+				best = ind
+			}
+		}
+		return best
+	}
+}
+
+func spinWheel(weights []float64, r *rand.Rand) int {
+	sum := 0.0
+	for _, w := range weights {
+		sum = sum + w
+	}
+	u := r.Float64() * sum
+	for i, w := range weights {
+		u = u - w
+		if u <= 0.0 {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
+
+func crossGenes(p1 Individual, p2 Individual, b_low mat.Vector, b_up mat.Vector,
+	params *Params, r *rand.Rand) []Individual {
+	n := len(p1.genes)
+	c1 := &IndividualType{genes: append([]float64(nil), p1.genes...)}
+	c2 := &IndividualType{genes: append([]float64(nil), p2.genes...)}
+	if r.Float64() >= params.Crossover_rate {
+		return []Individual{c1, c2}
+	}
+	for i := 0; i < n; i++ {
+		x1 := p1.genes[i]
+		x2 := p2.genes[i]
+		switch params.Crossover {
+		case BLX_alpha:
+			low := math.Min(x1, x2)
+			d := math.Abs(x1 - x2)
+			c1.genes[i] = low - params.Alpha*d + r.Float64()*(1.0+2.0*params.Alpha)*d
+			c2.genes[i] = low - params.Alpha*d + r.Float64()*(1.0+2.0*params.Alpha)*d
+		case Uniform:
+			if r.Float64() < 0.5 {
+				c1.genes[i], c2.genes[i] = x2, x1
+			}
+		default:
+			if r.Float64() < 0.5 {
+				u := r.Float64()
+				var beta float64
+				if u <= 0.5 {
+					beta = math.Pow(2.0*u, 1.0/(params.Eta_c+1.0))
+				} else {
+					beta = math.Pow(1.0/(2.0*(1.0-u)), 1.0/(params.Eta_c+1.0))
+				}
+				c1.genes[i] = 0.5 * ((1.0+beta)*x1 + (1.0-beta)*x2)
+				c2.genes[i] = 0.5 * ((1.0-beta)*x1 + (1.0+beta)*x2)
+			}
+		}
+		c1.genes[i] = clamp(c1.genes[i], b_low.AtVec(i), b_up.AtVec(i))
+		c2.genes[i] = clamp(c2.genes[i], b_low.AtVec(i), b_up.AtVec(i))
+	}
+	return []Individual{c1, c2}
+}
+
+func mutateGenes(c Individual, b_low mat.Vector, b_up mat.Vector, params *Params, r *rand.Rand) {
+	for i, x := range c.genes {
+		if r.Float64() >= params.Mutation_rate {
+			continue
+		}
+		width := b_up.AtVec(i) - b_low.AtVec(i)
+		if params.Mutation == Gaussian {
+			x = x + params.Sigma*width*r.NormFloat64()
+		} else {
+			u := r.Float64()
+			var delta float64
+			if u < 0.5 {
+				delta = math.Pow(2.0*u, 1.0/(params.Eta_m+1.0)) - 1.0
+			} else {
+				delta = 1.0 - math.Pow(2.0*(1.0-u), 1.0/(params.Eta_m+1.0))
+			}
+			x = x + delta*width
+		}
+		c.genes[i] = clamp(x, b_low.AtVec(i), b_up.AtVec(i))
+	}
+}
+
+func crossBits(p1 Individual, p2 Individual, params *Params, r *rand.Rand) []Individual {
+	c1 := &IndividualType{bits: append([]bool(nil), p1.bits...)}
+	c2 := &IndividualType{bits: append([]bool(nil), p2.bits...)}
+	if r.Float64() < params.Crossover_rate {
+		for i := range c1.bits {
+			if r.Float64() < 0.5 {
+				c1.bits[i], c2.bits[i] = c2.bits[i], c1.bits[i]
+			}
+		}
+	}
+	return []Individual{c1, c2}
+}
+
+func mutateBits(c Individual, params *Params, r *rand.Rand) {
+	for i := range c.bits {
+		if r.Float64() < params.Mutation_rate {
+			c.bits[i] = !c.bits[i]
+		}
+	}
+}
+
+func decode(ind Individual, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
+	n := b_low.Len()
+	if params.Encoding != Binary {
+		return mat.NewVecDense(n, append([]float64(nil), ind.genes...))
+	}
+	x := mat.NewVecDense(n, nil)
+	max := math.Pow(2.0, float64(params.Bits)) - 1.0
+	for i := 0; i < n; i++ {
+		k := 0.0
+		for _, bit := range ind.bits[i*params.Bits : (i+1)*params.Bits] {
+			k = 2.0 * k
+			if bit {
+				k = k + 1.0
+			}
+		}
+		x.SetVec(i, b_low.AtVec(i)+k/max*(b_up.AtVec(i)-b_low.AtVec(i)))
+	}
+	return x
+}
+
+func initPopulation(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) []Individual {
+	r := common.GetNewRand()
+	res := make([]Individual, params.N_individuals)
+	for i := range res {
+		ind := &IndividualType{}
+		if params.Encoding == Binary {
+			ind.bits = make([]bool, b_low.Len()*params.Bits)
+			for k := range ind.bits {
+				ind.bits[k] = r.Intn(2) == 1
+			}
+		} else {
+			ind.genes = common.RandomDataInBounds(b_low, b_up).RawVector().Data
+		}
+		ind.value = f(decode(ind, b_low, b_up, params))
+		res[i] = ind
+	}
+	return res
+}
+
+func sortPopulation(population []Individual) {
+	sort.SliceStable(population, func(i, j int) bool {
+		return population[i].value < population[j].value
+	})
+}
+
+func clamp(e float64, low float64, up float64) float64 {
+	return math.Min(math.Max(low, e), up)
+}
@@ -0,0 +1,48 @@
+package ga
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestGa(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	params := &Params{
+		N_individuals:  100,
+		Max_iter:       100,
+		Selection:      Tournament,
+		Crossover:      SBX,
+		Crossover_rate: 0.9,
+		Mutation:       Polynomial,
+		Elitism:        2}
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	min := Optimize(f, b_low, b_up, params)
+	t.Log(min)
+	t.Log(f(min))
+}
+
+func TestGaBinarySteadyState(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	params := &Params{
+		N_individuals:  100,
+		Max_iter:       100,
+		Encoding:       Binary,
+		Bits:           20,
+		Selection:      Rank,
+		Crossover_rate: 0.9,
+		Replacement:    SteadyState}
+	f := func(x mat.Vector) float64 {
+		return math.Pow(x.AtVec(0)-1.0, 2.0) + math.Pow(x.AtVec(1)+2.0, 2.0)
+	}
+	min := Optimize(f, b_low, b_up, params)
+	t.Log(min)
+	t.Log(f(min))
+}
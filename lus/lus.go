@@ -1,44 +1,122 @@
 package lus
 
 import (
+	"fmt"
 	"math"
+	"math/rand"
 
 	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/sampling"
 
 	"gonum.org/v1/gonum/mat"
 )
 
+// Max_iter is the total number of evaluations, shared equally by the starts.
+// A search ends (or restarts) once the diameter of the sampling range falls below Precision.
 type Params = struct {
-	Max_iter  int
-	Precision float64
+	Max_iter        int
+	Precision       float64
+	Init            sampling.Sampler // starting points, nil means uniform for a single start and LatinHypercube otherwise
+	N_starts        int              // independent searches from different starting points, defaults to 1
+	Restart         bool             // a collapsed search restarts from a random point with the full range
+	Decrease_factor []float64        // per dimension factor of the range after a failure, length n, defaults to 2^(-1/(3 n))
 }
 
-func Optimize(b_low mat.Vector, b_up mat.Vector, params *Params, f common.Target) mat.Vector {
-	n := b_low.Len()
-	beta := 1.0 / 3.0
-	q := math.Pow(2.0, -beta/float64(n))
-	x := common.RandomDataInBounds(b_low, b_up)
-	d := mat.NewVecDense(n, nil)
-	d.SubVec(b_up, b_low)
-	best_value := f(x)
-	diam := math.Max(math.Abs(mat.Max(b_low)), math.Abs(mat.Max(b_up)))
-	for iter := 0; iter < params.Max_iter && diam > params.Precision; iter++ {
-		d_m := mat.NewVecDense(n, nil)
-		d_m.ScaleVec(-1.0, d)
-		a := common.RandomDataInBounds(d_m, d)
-		y := mat.NewVecDense(n, nil)
-		y.AddVec(x, a)
-		value := f(y)
-		if value < best_value {
-			x = y
-			if math.Abs(best_value-value) < params.Precision {
+// Optimize runs the Local Unimodal Sampling of Pedersen. Around the current point a candidate is drawn uniformly
+// from the sampling range intersected with the bounds. The point moves on improvement, otherwise the range shrinks.
+// An error is returned if Decrease_factor does not match the dimension of the bounds.
+func Optimize(b_low mat.Vector, b_up mat.Vector, params *Params, f common.Target) (mat.Vector, error) {
+	r := common.GetNewRand()
+	q, err := decreaseFactors(b_low.Len(), params)
+	if err != nil {
+		return nil, err
+	}
+	n_starts := params.N_starts
+	if n_starts < 1 {
+		n_starts = 1
+	}
+	// every start evaluates its starting point
+	if n_starts > params.Max_iter {
+		n_starts = int(math.Max(float64(params.Max_iter), 1.0))
+	}
+	init := params.Init
+	if init == nil && n_starts > 1 {
+		init = sampling.LatinHypercube
+	}
+	budget := params.Max_iter / n_starts
+	var best *mat.VecDense
+	best_value := math.Inf(1)
+	for _, x := range sampling.Sample(init, b_low, b_up, n_starts) {
+		y, value := search(f, x, b_low, b_up, budget, q, params, r)
+		if best == nil || value < best_value {
+			best = y
+			best_value = value
+		}
+	}
+	return best, nil
+}
+
+// search uses budget evaluations including the one of x
+func search(f common.Target, x *mat.VecDense, b_low mat.Vector, b_up mat.Vector, budget int,
+	q *mat.VecDense, params *Params, r *rand.Rand) (*mat.VecDense, float64) {
+	d := fullRange(b_low, b_up)
+	value := f(x)
+	best, best_value := x, value
+	for iter := 1; iter < budget; iter++ {
+		if mat.Norm(d, 2) <= params.Precision {
+			if !params.Restart {
 				break
 			}
-			best_value = value
+			x = common.RandomDataInBounds(b_low, b_up)
+			value = f(x)
+			d = fullRange(b_low, b_up)
 		} else {
-			d.ScaleVec(q, d)
-			diam = q * diam
+			y := sampleAround(x, d, b_low, b_up, r)
+			if y_value := f(y); y_value < value {
+				x, value = y, y_value
+			} else {
+				d.MulElemVec(d, q)
+			}
 		}
+		if value < best_value {
+			best, best_value = x, value
+		}
+	}
+	return best, best_value
+}
+
+// uniform in [x - d, x + d] intersected with the bounds
+func sampleAround(x mat.Vector, d mat.Vector, b_low mat.Vector, b_up mat.Vector, r *rand.Rand) *mat.VecDense {
+	y := mat.NewVecDense(x.Len(), nil)
+	for i := 0; i < x.Len(); i++ {
+		low := math.Max(x.AtVec(i)-d.AtVec(i), b_low.AtVec(i))
+		up := math.Min(x.AtVec(i)+d.AtVec(i), b_up.AtVec(i))
+		y.SetVec(i, low+r.Float64()*(up-low))
+	}
+	return y
+}
+
+func fullRange(b_low mat.Vector, b_up mat.Vector) *mat.VecDense {
+	d := mat.NewVecDense(b_low.Len(), nil)
+	d.SubVec(b_up, b_low)
+	common.ApplyElementwise(d, func(e float64, idx int) float64 {
+		return math.Abs(e)
+	})
+	return d
+}
+
+func decreaseFactors(n int, params *Params) (*mat.VecDense, error) {
+	if params.Decrease_factor != nil {
+		if len(params.Decrease_factor) != n {
+			return nil, fmt.Errorf("lus: Decrease_factor has length %d, but the bounds have dimension %d",
+				len(params.Decrease_factor), n)
+		}
+		return mat.NewVecDense(n, append([]float64(nil), params.Decrease_factor...)), nil
+	}
+	beta := 1.0 / 3.0
+	q := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		q.SetVec(i, math.Pow(2.0, -beta/float64(n)))
 	}
-	return x
+	return q, nil
 }
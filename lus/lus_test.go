@@ -15,5 +15,61 @@ func TestLsu(t *testing.T) {
 		x1 := x.AtVec(1)
 		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
 	}
-	t.Log(Optimize(b_low, b_up, &Params{Max_iter: 1000, Precision: 0.01}, f))
+	min, err := Optimize(b_low, b_up, &Params{Max_iter: 1000, Precision: 0.01}, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(min)
+}
+
+func TestLusMultiStart(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, 0.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 0.001})
+	// minimum at the upper bound of the second, much smaller dimension
+	f := func(x mat.Vector) float64 {
+		return math.Pow(x.AtVec(0)-3.0, 2.0) - 1000.0*x.AtVec(1)
+	}
+	params := &Params{
+		Max_iter:        2000,
+		Precision:       1e-6,
+		N_starts:        4,
+		Restart:         true,
+		Decrease_factor: []float64{0.9, 0.99}}
+	min, err := Optimize(b_low, b_up, params, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(min, f(min))
+	if min.AtVec(1) < b_low.AtVec(1) || min.AtVec(1) > b_up.AtVec(1) {
+		t.Fatal("minimum out of bounds", min)
+	}
+}
+
+func TestDecreaseFactors(t *testing.T) {
+	b_low := mat.NewVecDense(3, nil)
+	b_up := mat.NewVecDense(3, []float64{1.0, 1.0, 1.0})
+	f := func(x mat.Vector) float64 {
+		return x.AtVec(0)
+	}
+	_, err := Optimize(b_low, b_up, &Params{Max_iter: 100, Decrease_factor: []float64{0.9, 0.9}}, f)
+	t.Log(err)
+	if err == nil {
+		t.Fatal("TestDecreaseFactors fails")
+	}
+}
+
+func TestLusStartsBudget(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-1.0, -1.0})
+	b_up := mat.NewVecDense(2, []float64{1.0, 1.0})
+	evaluations := 0
+	f := func(x mat.Vector) float64 {
+		evaluations++
+		return x.AtVec(0)*x.AtVec(0) + x.AtVec(1)*x.AtVec(1)
+	}
+	if _, err := Optimize(b_low, b_up, &Params{Max_iter: 5, N_starts: 20}, f); err != nil {
+		t.Fatal(err)
+	}
+	if evaluations > 5 {
+		t.Fatal("Max_iter exceeded", evaluations)
+	}
 }
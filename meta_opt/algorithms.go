@@ -0,0 +1,53 @@
+package meta_opt
+
+import (
+	"github.com/applied-math-coding/heuristic/abc"
+	"github.com/applied-math-coding/heuristic/de"
+	"github.com/applied-math-coding/heuristic/pso"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// PSO tunes Omega, Phi_p and Phi_g in the ranges of Pedersen, all other fields are taken from base.
+func PSO(base pso.Params) *Algorithm {
+	return &Algorithm{
+		Parameters: []Parameter{
+			{Name: "Omega", Low: -2.0, Up: 2.0},
+			{Name: "Phi_p", Low: -4.0, Up: 4.0},
+			{Name: "Phi_g", Low: -4.0, Up: 4.0}},
+		Run: func(values map[string]float64, problem *Problem) mat.Vector {
+			params := base
+			params.Omega = values["Omega"]
+			params.Phi_p = values["Phi_p"]
+			params.Phi_g = values["Phi_g"]
+			return pso.Optimize(problem.F, problem.B_low, problem.B_up, &params)
+		}}
+}
+
+// DE tunes F and CR, all other fields are taken from base.
+func DE(base de.Params) *Algorithm {
+	return &Algorithm{
+		Parameters: []Parameter{
+			{Name: "F", Low: 0.0, Up: 2.0},
+			{Name: "CR", Low: 0.0, Up: 1.0}},
+		Run: func(values map[string]float64, problem *Problem) mat.Vector {
+			params := base
+			params.F = values["F"]
+			params.CR = values["CR"]
+			return de.Optimize(problem.F, problem.B_low, problem.B_up, &params)
+		}}
+}
+
+// ABC tunes Abandon_limit and the GABC factor C, all other fields are taken from base.
+func ABC(base abc.Params) *Algorithm {
+	return &Algorithm{
+		Parameters: []Parameter{
+			{Name: "Abandon_limit", Low: 1.0, Up: 200.0, Integer: true},
+			{Name: "C", Low: 0.01, Up: 3.0}},
+		Run: func(values map[string]float64, problem *Problem) mat.Vector {
+			params := base
+			params.Abandon_limit = int(values["Abandon_limit"])
+			params.C = values["C"]
+			return abc.Optimize(problem.F, problem.B_low, problem.B_up, &params)
+		}}
+}
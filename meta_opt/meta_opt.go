@@ -0,0 +1,171 @@
+package meta_opt
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+
+	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/lus"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Problem is one training problem of the tuning.
+type Problem = struct {
+	F     common.Target
+	B_low mat.Vector
+	B_up  mat.Vector
+}
+
+// Parameter is a tunable parameter of an algorithm. Integer parameters are rounded.
+type Parameter = struct {
+	Name    string
+	Low     float64
+	Up      float64
+	Integer bool
+}
+
+// Algorithm runs an optimizer with the given parameter values on a problem and returns the best position found.
+// Run has to evaluate the target only through problem.F, so that the evaluations can be counted.
+type Algorithm = struct {
+	Parameters []Parameter
+	Run        func(values map[string]float64, problem *Problem) mat.Vector
+}
+
+// Statistic aggregates the results of the runs on one problem.
+type Statistic int
+
+const (
+	Mean     Statistic = iota
+	Quantile           // e.g. the median or a pessimistic 0.9-quantile, see Params.Quantile
+)
+
+type Params = struct {
+	N_runs          int // runs per problem and configuration, defaults to 5
+	Statistic       Statistic
+	Quantile        float64 // defaults to 0.5
+	Max_configs     int     // configurations tried by the meta-optimizer, defaults to 100
+	Max_evaluations int64   // limit of target evaluations over the whole tuning, 0 means unlimited
+	Meta_params     *lus.Params
+}
+
+// Result holds the best configuration and its meta-fitness.
+type Result = struct {
+	Values             map[string]float64
+	Fitness            float64
+	Configs            int   // configurations evaluated completely
+	Evaluations        int64 // target evaluations spent
+	Config_evaluations int64 // most target evaluations spent on a single configuration
+}
+
+// Optimize tunes the parameters of algorithm by LUS on the meta-fitness of Pedersen:
+// the sum over all problems of the statistic of the best values found in N_runs runs.
+// The meta-optimizer works on the unit cube, which is mapped onto the parameter ranges.
+// Once Max_evaluations is reached, the remaining configurations are skipped, so the budget is exceeded
+// by less than Config_evaluations. An error of the meta-optimizer, i.e. of Meta_params, is returned.
+func Optimize(algorithm *Algorithm, problems []*Problem, params *Params) (*Result, error) {
+	k := len(algorithm.Parameters)
+	unit_low := mat.NewVecDense(k, nil)
+	unit_up := mat.NewVecDense(k, nil)
+	for i := 0; i < k; i++ {
+		unit_up.SetVec(i, 1.0)
+	}
+	meta_params := params.Meta_params
+	if meta_params == nil {
+		meta_params = &lus.Params{Precision: 0.001}
+	}
+	meta := *meta_params
+	if meta.Max_iter <= 0 {
+		meta.Max_iter = params.Max_configs
+		if meta.Max_iter <= 0 {
+			meta.Max_iter = 100
+		}
+	}
+	res := &Result{Fitness: math.Inf(1)}
+	var evaluations int64
+	counted := make([]*Problem, len(problems))
+	for i, p := range problems {
+		f := p.F
+		counted[i] = &Problem{
+			F: func(x mat.Vector) float64 {
+				atomic.AddInt64(&evaluations, 1)
+				return f(x)
+			},
+			B_low: p.B_low,
+			B_up:  p.B_up}
+	}
+	_, err := lus.Optimize(unit_low, unit_up, &meta, func(u mat.Vector) float64 {
+		if params.Max_evaluations > 0 && atomic.LoadInt64(&evaluations) >= params.Max_evaluations {
+			return math.Inf(1)
+		}
+		values := ToValues(algorithm.Parameters, u)
+		start := atomic.LoadInt64(&evaluations)
+		fitness := MetaFitness(algorithm, values, counted, params)
+		if spent := atomic.LoadInt64(&evaluations) - start; spent > res.Config_evaluations {
+			res.Config_evaluations = spent
+		}
+		if params.Max_evaluations > 0 && atomic.LoadInt64(&evaluations) > params.Max_evaluations {
+			// the budget ran out during this configuration
+			return math.Inf(1)
+		}
+		res.Configs++
+		if fitness < res.Fitness {
+			res.Fitness = fitness
+			res.Values = values
+		}
+		return fitness
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Evaluations = atomic.LoadInt64(&evaluations)
+	return res, nil
+}
+
+// MetaFitness runs the algorithm N_runs times on every problem and sums the statistic of the best values.
+func MetaFitness(algorithm *Algorithm, values map[string]float64, problems []*Problem, params *Params) float64 {
+	n_runs := params.N_runs
+	if n_runs <= 0 {
+		n_runs = 5
+	}
+	res := 0.0
+	for _, p := range problems {
+		results := make([]float64, n_runs)
+		for i := range results {
+			results[i] = p.F(algorithm.Run(values, p))
+		}
+		res = res + aggregate(results, params)
+	}
+	return res
+}
+
+func aggregate(results []float64, params *Params) float64 {
+	if params.Statistic == Quantile {
+		q := params.Quantile
+		if q <= 0.0 {
+			q = 0.5
+		}
+		sorted := append([]float64(nil), results...)
+		sort.Float64s(sorted)
+		return sorted[int(math.Min(math.Floor(q*float64(len(sorted))), float64(len(sorted)-1)))]
+	}
+	sum := 0.0
+	for _, e := range results {
+		sum = sum + e
+	}
+	return sum / float64(len(results))
+}
+
+// ToValues maps a point of the unit cube onto the parameter ranges.
+func ToValues(parameters []Parameter, u mat.Vector) map[string]float64 {
+	res := make(map[string]float64)
+	for i, p := range parameters {
+		v := p.Low + u.AtVec(i)*(p.Up-p.Low)
+		if p.Integer {
+			v = math.Round(v)
+		}
+		res[p.Name] = v
+	}
+	return res
+}
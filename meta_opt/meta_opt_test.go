@@ -0,0 +1,72 @@
+package meta_opt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/applied-math-coding/heuristic/abc"
+	"github.com/applied-math-coding/heuristic/de"
+	"github.com/applied-math-coding/heuristic/pso"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func trainingProblems() []*Problem {
+	sphere := func(x mat.Vector) float64 {
+		res := 0.0
+		for i := 0; i < x.Len(); i++ {
+			res = res + x.AtVec(i)*x.AtVec(i)
+		}
+		return res
+	}
+	rastrigin := func(x mat.Vector) float64 {
+		res := 10.0 * float64(x.Len())
+		for i := 0; i < x.Len(); i++ {
+			res = res + math.Pow(x.AtVec(i), 2.0) - 10.0*math.Cos(2.0*math.Pi*x.AtVec(i))
+		}
+		return res
+	}
+	b_low := mat.NewVecDense(5, []float64{-5.0, -5.0, -5.0, -5.0, -5.0})
+	b_up := mat.NewVecDense(5, []float64{5.0, 5.0, 5.0, 5.0, 5.0})
+	return []*Problem{
+		{F: sphere, B_low: b_low, B_up: b_up},
+		{F: rastrigin, B_low: b_low, B_up: b_up}}
+}
+
+func TestOptimize(t *testing.T) {
+	algorithms := map[string]*Algorithm{
+		"pso": PSO(pso.Params{N_particles: 20, Max_iter: 50}),
+		"de":  DE(de.Params{N_agents: 20, Max_iter: 50}),
+		"abc": ABC(abc.Params{N_bees: 10, Max_iter: 50, Search: abc.GABC})}
+	for name, algorithm := range algorithms {
+		params := &Params{
+			N_runs:      3,
+			Statistic:   Quantile,
+			Quantile:    0.5,
+			Max_configs: 30}
+		res, err := Optimize(algorithm, trainingProblems(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Log(name, res.Values, res.Fitness, res.Configs, res.Evaluations)
+	}
+}
+
+func TestOptimizeBudget(t *testing.T) {
+	params := &Params{
+		N_runs:          2,
+		Max_configs:     1000,
+		Max_evaluations: 50000}
+	res, err := Optimize(DE(de.Params{N_agents: 10, Max_iter: 50}), trainingProblems(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(res.Values, res.Fitness, res.Configs, res.Evaluations, res.Config_evaluations)
+	// N_runs * problems * (initial agents + Max_iter + 1 generations + evaluation of the result)
+	if res.Config_evaluations != 2*2*(10+51*10+1) {
+		t.Fatal("unexpected evaluations per configuration", res.Config_evaluations)
+	}
+	if res.Evaluations >= params.Max_evaluations+res.Config_evaluations {
+		t.Fatal("budget exceeded by more than one configuration", res.Evaluations)
+	}
+}
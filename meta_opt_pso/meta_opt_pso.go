@@ -14,7 +14,8 @@ func Optimize(f common.Target, b_low mat.Vector, b_up mat.Vector) *pso.Params {
 	learning_rate_max := 1.0
 	lus_b_low := mat.NewVecDense(4, []float64{-omega_max, -phi_max, -phi_max, 0.01})
 	lus_b_up := mat.NewVecDense(4, []float64{omega_max, phi_max, phi_max, learning_rate_max})
-	optimal := lus.Optimize(
+	// the bounds and params are fixed, so lus cannot fail
+	optimal, _ := lus.Optimize(
 		lus_b_low,
 		lus_b_up,
 		&lus.Params{Max_iter: 1000, Precision: 0.01},
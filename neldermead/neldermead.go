@@ -0,0 +1,205 @@
+package neldermead
+
+import (
+	"math"
+	"sort"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Params = struct {
+	Max_iter     int
+	Max_restarts int     // how often a degenerated simplex may be rebuilt
+	Tol_x        float64 // stop if all vertices are this close to the best one
+	Tol_f        float64 // stop if the spread of values within the simplex is below this
+	Step         float64 // initial edge length relative to b_up - b_low, defaults to 0.05
+}
+
+type vertex = struct {
+	z     *mat.VecDense // position in the unconstrained space
+	value float64
+}
+
+// volume (relative to edge lengths) below which the simplex is considered degenerated
+var degenerate_volume = 1e-10
+
+// Optimize runs a Nelder-Mead simplex search starting at x_0. Typically x_0 is the best point found by
+// one of the global optimizers (pso, de, abc) and this is used to polish it.
+// The coefficients are adapted to the dimension (Gao, Han 2012). The bounds are enforced by
+// the transformation x = b_low + (b_up - b_low) * (1 + sin(z)) / 2 such that the simplex moves freely in z.
+func Optimize(f common.Target, x_0 mat.Vector, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
+	n := x_0.Len()
+	alpha := 1.0
+	beta := 1.0 + 2.0/float64(n)
+	gamma := 0.75 - 1.0/(2.0*float64(n))
+	delta := 1.0 - 1.0/float64(n)
+	step := params.Step
+	if step <= 0.0 {
+		step = 0.05
+	}
+	g := func(z mat.Vector) float64 {
+		return f(toOuter(z, b_low, b_up))
+	}
+	simplex := initSimplex(g, x_0, b_low, b_up, step)
+	restarts := 0
+	for iter := 0; iter < params.Max_iter; iter++ {
+		sortSimplex(simplex)
+		if isConverged(simplex, b_low, b_up, params) {
+			break
+		}
+		if restarts < params.Max_restarts && isDegenerated(simplex) {
+			simplex = initSimplex(g, toOuter(simplex[0].z, b_low, b_up), b_low, b_up, step)
+			restarts++
+			continue
+		}
+		best := simplex[0]
+		worst := simplex[n]
+		c := centroid(simplex[:n])
+		z_r := mat.NewVecDense(n, nil)
+		z_r.AddScaledVec(c, alpha, subVec(c, worst.z))
+		value_r := g(z_r)
+		switch {
+		case value_r < best.value:
+			z_e := mat.NewVecDense(n, nil)
+			z_e.AddScaledVec(c, beta, subVec(z_r, c))
+			value_e := g(z_e)
+			if value_e < value_r {
+				replaceWorst(simplex, z_e, value_e)
+			} else {
+				replaceWorst(simplex, z_r, value_r)
+			}
+		case value_r < simplex[n-1].value:
+			replaceWorst(simplex, z_r, value_r)
+		case value_r < worst.value:
+			z_oc := mat.NewVecDense(n, nil)
+			z_oc.AddScaledVec(c, gamma, subVec(z_r, c))
+			value_oc := g(z_oc)
+			if value_oc <= value_r {
+				replaceWorst(simplex, z_oc, value_oc)
+			} else {
+				shrink(g, simplex, delta)
+			}
+		default:
+			z_ic := mat.NewVecDense(n, nil)
+			z_ic.AddScaledVec(c, -gamma, subVec(c, worst.z))
+			value_ic := g(z_ic)
+			if value_ic < worst.value {
+				replaceWorst(simplex, z_ic, value_ic)
+			} else {
+				shrink(g, simplex, delta)
+			}
+		}
+	}
+	sortSimplex(simplex)
+	return toOuter(simplex[0].z, b_low, b_up)
+}
+
+func initSimplex(g common.Target, x_0 mat.Vector, b_low mat.Vector, b_up mat.Vector, step float64) []*vertex {
+	n := x_0.Len()
+	simplex := make([]*vertex, n+1)
+	z_0 := toInner(x_0, b_low, b_up)
+	simplex[0] = &vertex{z: z_0, value: g(z_0)}
+	for i := 0; i < n; i++ {
+		x := mat.NewVecDense(n, nil)
+		x.CopyVec(x_0)
+		h := step * (b_up.AtVec(i) - b_low.AtVec(i))
+		if x.AtVec(i)+h > b_up.AtVec(i) {
+			h = -h
+		}
+		x.SetVec(i, x.AtVec(i)+h)
+		z := toInner(x, b_low, b_up)
+		simplex[i+1] = &vertex{z: z, value: g(z)}
+	}
+	return simplex
+}
+
+func sortSimplex(simplex []*vertex) {
+	sort.SliceStable(simplex, func(i, j int) bool {
+		return simplex[i].value < simplex[j].value
+	})
+}
+
+// the simplex is small enough in the original space and the values hardly differ
+func isConverged(simplex []*vertex, b_low mat.Vector, b_up mat.Vector, params *Params) bool {
+	if simplex[len(simplex)-1].value-simplex[0].value > params.Tol_f {
+		return false
+	}
+	best := toOuter(simplex[0].z, b_low, b_up)
+	for _, s := range simplex[1:] {
+		x := toOuter(s.z, b_low, b_up)
+		for i := 0; i < x.Len(); i++ {
+			if math.Abs(x.AtVec(i)-best.AtVec(i)) > params.Tol_x {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// compares the volume spanned by the edges with the one of a cube having the same edge lengths
+func isDegenerated(simplex []*vertex) bool {
+	n := len(simplex) - 1
+	edges := mat.NewDense(n, n, nil)
+	scale := 1.0
+	for j, s := range simplex[1:] {
+		e := subVec(s.z, simplex[0].z)
+		norm := mat.Norm(e, 2)
+		if norm == 0.0 {
+			return true
+		}
+		scale = scale * norm
+		edges.SetCol(j, e.RawVector().Data)
+	}
+	return math.Abs(mat.Det(edges))/scale < degenerate_volume
+}
+
+func centroid(vertices []*vertex) *mat.VecDense {
+	c := mat.NewVecDense(vertices[0].z.Len(), nil)
+	for _, s := range vertices {
+		c.AddVec(c, s.z)
+	}
+	c.ScaleVec(1.0/float64(len(vertices)), c)
+	return c
+}
+
+func replaceWorst(simplex []*vertex, z *mat.VecDense, value float64) {
+	simplex[len(simplex)-1] = &vertex{z: z, value: value}
+}
+
+func shrink(g common.Target, simplex []*vertex, delta float64) {
+	best := simplex[0].z
+	for _, s := range simplex[1:] {
+		s.z.AddScaledVec(best, delta, subVec(s.z, best))
+		s.value = g(s.z)
+	}
+}
+
+func subVec(a mat.Vector, b mat.Vector) *mat.VecDense {
+	res := mat.NewVecDense(a.Len(), nil)
+	res.SubVec(a, b)
+	return res
+}
+
+func toInner(x mat.Vector, b_low mat.Vector, b_up mat.Vector) *mat.VecDense {
+	z := mat.NewVecDense(x.Len(), nil)
+	for i := 0; i < x.Len(); i++ {
+		width := b_up.AtVec(i) - b_low.AtVec(i)
+		if width == 0.0 {
+			continue
+		}
+		s := 2.0*(x.AtVec(i)-b_low.AtVec(i))/width - 1.0
+		z.SetVec(i, math.Asin(math.Min(math.Max(s, -1.0), 1.0)))
+	}
+	return z
+}
+
+func toOuter(z mat.Vector, b_low mat.Vector, b_up mat.Vector) *mat.VecDense {
+	x := mat.NewVecDense(z.Len(), nil)
+	for i := 0; i < z.Len(); i++ {
+		width := b_up.AtVec(i) - b_low.AtVec(i)
+		x.SetVec(i, b_low.AtVec(i)+width*(1.0+math.Sin(z.AtVec(i)))/2.0)
+	}
+	return x
+}
@@ -0,0 +1,42 @@
+package neldermead
+
+import (
+	"math"
+	"testing"
+
+	"github.com/applied-math-coding/heuristic/de"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestNelderMead(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	params := &Params{Max_iter: 1000, Max_restarts: 5, Tol_x: 1e-8, Tol_f: 1e-12}
+	x_0 := de.Optimize(f, b_low, b_up, &de.Params{N_agents: 20, Max_iter: 5, F: 0.8, CR: 0.9})
+	min := Optimize(f, x_0, b_low, b_up, params)
+	t.Log(min)
+	t.Log(f(min))
+	if f(min) > f(x_0) {
+		t.Fatal("TestNelderMead fails")
+	}
+}
+
+func TestNelderMeadOnBound(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{1.0, 1.0})
+	b_up := mat.NewVecDense(2, []float64{3.0, 3.0})
+	f := func(x mat.Vector) float64 {
+		return math.Pow(x.AtVec(0), 2.0) + math.Pow(x.AtVec(1), 2.0)
+	}
+	x_0 := mat.NewVecDense(2, []float64{2.5, 2.5})
+	min := Optimize(f, x_0, b_low, b_up, &Params{Max_iter: 1000, Tol_x: 1e-8, Tol_f: 1e-12})
+	t.Log(min)
+	if math.Abs(min.AtVec(0)-1.0) > 1e-4 || math.Abs(min.AtVec(1)-1.0) > 1e-4 {
+		t.Fatal("TestNelderMeadOnBound fails")
+	}
+}
@@ -0,0 +1,93 @@
+package perm
+
+import (
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Permutation of 0..n-1. For routing problems it is the order in which the nodes are visited,
+// for assignment problems p[i] is the location of facility i.
+type Permutation = []int
+
+func Random(n int, r *rand.Rand) Permutation {
+	return r.Perm(n)
+}
+
+func Copy(p Permutation) Permutation {
+	return append(Permutation(nil), p...)
+}
+
+// TourLength is the length of the closed tour visiting the nodes in the order of p.
+func TourLength(dist mat.Matrix, p Permutation) float64 {
+	res := 0.0
+	for k := range p {
+		res = res + dist.At(p[k], p[(k+1)%len(p)])
+	}
+	return res
+}
+
+// AssignmentCost is the objective of the quadratic assignment problem: sum of flow(i,j) * dist(p[i],p[j]).
+func AssignmentCost(flow mat.Matrix, dist mat.Matrix, p Permutation) float64 {
+	res := 0.0
+	for i := range p {
+		for j := range p {
+			res = res + flow.At(i, j)*dist.At(p[i], p[j])
+		}
+	}
+	return res
+}
+
+// TwoOpt improves a closed tour by reversing segments as long as this shortens the tour.
+// dist is assumed to be symmetric.
+func TwoOpt(dist mat.Matrix, p Permutation) Permutation {
+	res := Copy(p)
+	n := len(res)
+	improved := true
+	for improved {
+		improved = false
+		for i := 0; i < n-1; i++ {
+			for j := i + 2; j < n; j++ {
+				a, b := res[i], res[i+1]
+				c, d := res[j], res[(j+1)%n]
+				if a == d {
+					continue
+				}
+				delta := dist.At(a, c) + dist.At(b, d) - dist.At(a, b) - dist.At(c, d)
+				if delta < -1e-12 {
+					reverse(res[i+1 : j+1])
+					improved = true
+				}
+			}
+		}
+	}
+	return res
+}
+
+// Swap returns a copy of p with two random positions exchanged.
+func Swap(p Permutation, r *rand.Rand) Permutation {
+	res := Copy(p)
+	i := r.Intn(len(res))
+	j := r.Intn(len(res))
+	res[i], res[j] = res[j], res[i]
+	return res
+}
+
+// Insert returns a copy of p where the element at position i is moved to position j.
+func Insert(p Permutation, i int, j int) Permutation {
+	res := Copy(p)
+	e := res[i]
+	if i < j {
+		copy(res[i:j], res[i+1:j+1])
+	} else {
+		copy(res[j+1:i+1], res[j:i])
+	}
+	res[j] = e
+	return res
+}
+
+func reverse(p Permutation) {
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+}
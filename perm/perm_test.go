@@ -0,0 +1,44 @@
+package perm
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestTwoOpt(t *testing.T) {
+	// corners of a square, the tour 0-2-1-3 crosses itself
+	x := []float64{0.0, 1.0, 1.0, 0.0}
+	y := []float64{0.0, 0.0, 1.0, 1.0}
+	dist := mat.NewDense(4, 4, nil)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			dist.Set(i, j, math.Hypot(x[i]-x[j], y[i]-y[j]))
+		}
+	}
+	p := TwoOpt(dist, Permutation{0, 2, 1, 3})
+	t.Log(p)
+	if math.Abs(TourLength(dist, p)-4.0) > 1e-9 {
+		t.Fatal("TestTwoOpt fails")
+	}
+}
+
+func TestAssignmentCost(t *testing.T) {
+	flow := mat.NewDense(2, 2, []float64{0.0, 3.0, 3.0, 0.0})
+	dist := mat.NewDense(2, 2, []float64{0.0, 2.0, 2.0, 0.0})
+	t.Log(AssignmentCost(flow, dist, Random(2, common.GetNewRand())))
+}
+
+func TestInsert(t *testing.T) {
+	p := Permutation{0, 1, 2, 3, 4}
+	forward := Insert(p, 1, 3)
+	backward := Insert(p, 3, 1)
+	t.Log(forward, backward)
+	if !reflect.DeepEqual(forward, Permutation{0, 2, 3, 1, 4}) || !reflect.DeepEqual(backward, Permutation{0, 3, 1, 2, 4}) {
+		t.Fatal(forward, backward)
+	}
+}
@@ -0,0 +1,83 @@
+package portfolio
+
+import (
+	"github.com/applied-math-coding/heuristic/abc"
+	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/de"
+	"github.com/applied-math-coding/heuristic/lus"
+	"github.com/applied-math-coding/heuristic/pso"
+	"github.com/applied-math-coding/heuristic/sampling"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Member is an algorithm of the portfolio. Run should spend about the given number of evaluations of f
+// and start from the points of init. Optimize records the best position among the evaluations, further calls
+// of f beyond the given number return +Inf.
+type Member = struct {
+	Name string
+	Run  func(f common.Target, b_low mat.Vector, b_up mat.Vector, init sampling.Sampler, evaluations int)
+}
+
+// PSO derives Max_iter from the evaluations, all other fields are taken from base.
+func PSO(base pso.Params) *Member {
+	return &Member{
+		Name: "pso",
+		Run: func(f common.Target, b_low mat.Vector, b_up mat.Vector, init sampling.Sampler, evaluations int) {
+			params := base
+			params.Init = init
+			params.Max_iter = iterations(evaluations, params.N_particles, 1)
+			pso.Optimize(f, b_low, b_up, &params)
+		}}
+}
+
+// DE derives Max_iter from the evaluations, all other fields are taken from base.
+func DE(base de.Params) *Member {
+	return &Member{
+		Name: "de",
+		Run: func(f common.Target, b_low mat.Vector, b_up mat.Vector, init sampling.Sampler, evaluations int) {
+			params := base
+			params.Init = init
+			params.Max_iter = iterations(evaluations, params.N_agents, 1) - 1
+			de.Optimize(f, b_low, b_up, &params)
+		}}
+}
+
+// ABC derives Max_iter from the evaluations, all other fields are taken from base.
+func ABC(base abc.Params) *Member {
+	return &Member{
+		Name: "abc",
+		Run: func(f common.Target, b_low mat.Vector, b_up mat.Vector, init sampling.Sampler, evaluations int) {
+			params := base
+			params.Init = init
+			params.Max_iter = iterations(evaluations, params.N_bees, 2)
+			abc.Optimize(f, b_low, b_up, &params)
+		}}
+}
+
+// LUS spends all evaluations on a single search from the first point of init.
+// It evaluates nothing if the Decrease_factor of base does not match the dimension.
+func LUS(base lus.Params) *Member {
+	return &Member{
+		Name: "lus",
+		Run: func(f common.Target, b_low mat.Vector, b_up mat.Vector, init sampling.Sampler, evaluations int) {
+			params := base
+			params.Init = init
+			params.N_starts = 1
+			params.Max_iter = evaluations
+			lus.Optimize(b_low, b_up, &params, f)
+		}}
+}
+
+// iterations of a population of size n with per_member evaluations per member and iteration,
+// after the evaluation of the initial population, at least 1
+func iterations(evaluations int, n int, per_member int) int {
+	if n <= 0 {
+		return 1
+	}
+	res := (evaluations - n) / (n * per_member)
+	if res < 1 {
+		return 1
+	}
+	return res
+}
@@ -0,0 +1,211 @@
+package portfolio
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/sampling"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Selector is the bandit which chooses the member running the next slice of the budget.
+type Selector int
+
+const (
+	UCB             Selector = iota // highest mean reward plus the exploration bonus C sqrt(2 ln t / n) (Auer et al. 2002)
+	AdaptivePursuit                 // probabilities pursue the member of highest estimated reward (Thierens 2005)
+)
+
+type Params = struct {
+	Max_evaluations   int
+	Slice_evaluations int // evaluations per selection, defaults to Max_evaluations / 20
+	Selector          Selector
+	C                 float64          // UCB, weight of the exploration bonus, defaults to 1
+	P_min             float64          // AdaptivePursuit, minimal probability of a member, defaults to 0.1 / number of members
+	Alpha             float64          // AdaptivePursuit, adaptation rate of the reward estimates, defaults to 0.8
+	Beta              float64          // AdaptivePursuit, adaptation rate of the probabilities, defaults to 0.8
+	Migration         bool             // every run starts with the best position found so far among its initial points
+	Init              sampling.Sampler // initial points of the runs besides the migrated best, nil means uniform
+}
+
+// Result reports the best position and the member which found it.
+type Result = struct {
+	X           mat.Vector
+	Value       float64
+	Contributor string
+	Evaluations map[string]int // spent per member
+	Selections  map[string]int
+}
+
+// Optimize splits the evaluation budget into slices and lets the selector decide which member runs on the next slice.
+// At most Max_evaluations calls of f are made, the result is the best position among them.
+// The reward of a slice is the relative improvement of the best value, so members which improve fastest
+// receive more of the budget.
+func Optimize(f common.Target, b_low mat.Vector, b_up mat.Vector, members []*Member, params *Params) *Result {
+	r := common.GetNewRand()
+	slice := params.Slice_evaluations
+	if slice <= 0 {
+		slice = int(math.Max(float64(params.Max_evaluations/20), 1.0))
+	}
+	res := &Result{
+		Value:       math.Inf(1),
+		Evaluations: make(map[string]int),
+		Selections:  make(map[string]int)}
+	s := newSelector(len(members), params)
+	spent := 0
+	for t := 1; spent < params.Max_evaluations; t++ {
+		k := chooseMember(s, t, r)
+		m := members[k]
+		budget := slice
+		if params.Max_evaluations-spent < budget {
+			budget = params.Max_evaluations - spent
+		}
+		before := res.Value
+		evaluations := 0
+		// the best evaluated position within the bounds is recorded here, calls beyond the budget are not passed to f
+		counted := func(x mat.Vector) float64 {
+			if evaluations >= budget {
+				return math.Inf(1)
+			}
+			evaluations++
+			value := f(x)
+			if value < res.Value && inBounds(x, b_low, b_up) {
+				res.X = mat.VecDenseCopyOf(x)
+				res.Value = value
+				res.Contributor = m.Name
+			}
+			return value
+		}
+		m.Run(counted, b_low, b_up, initialPoints(res.X, params), budget)
+		updateSelector(s, k, reward(before, res.Value))
+		spent = spent + int(math.Max(float64(evaluations), 1.0))
+		res.Evaluations[m.Name] = res.Evaluations[m.Name] + evaluations
+		res.Selections[m.Name]++
+	}
+	return res
+}
+
+func inBounds(x mat.Vector, b_low mat.Vector, b_up mat.Vector) bool {
+	for i := 0; i < x.Len(); i++ {
+		if x.AtVec(i) < b_low.AtVec(i) || x.AtVec(i) > b_up.AtVec(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// relative improvement within [0, 1], the first improvement counts fully
+func reward(before float64, after float64) float64 {
+	if math.IsInf(before, 1) {
+		return 1.0
+	}
+	if after >= before {
+		return 0.0
+	}
+	return math.Min((before-after)/(math.Abs(before)+common.EPSILON), 1.0)
+}
+
+// the best position replaces the first initial point if migration is on
+func initialPoints(best mat.Vector, params *Params) sampling.Sampler {
+	if !params.Migration || best == nil {
+		return params.Init
+	}
+	return func(b_low mat.Vector, b_up mat.Vector, n int) []*mat.VecDense {
+		res := sampling.Sample(params.Init, b_low, b_up, n)
+		res[0] = mat.VecDenseCopyOf(best)
+		return res
+	}
+}
+
+type selector = struct {
+	selector      Selector
+	rewards       []float64 // sums for UCB, estimates for AdaptivePursuit
+	counts        []int
+	probabilities []float64
+	params        *Params
+}
+
+func newSelector(n int, params *Params) *selector {
+	s := &selector{
+		selector:      params.Selector,
+		rewards:       make([]float64, n),
+		counts:        make([]int, n),
+		probabilities: make([]float64, n),
+		params:        params}
+	for i := range s.probabilities {
+		s.probabilities[i] = 1.0 / float64(n)
+		if params.Selector == AdaptivePursuit {
+			s.rewards[i] = 1.0
+		}
+	}
+	return s
+}
+
+func chooseMember(s *selector, t int, r *rand.Rand) int {
+	if s.selector == AdaptivePursuit {
+		u := r.Float64()
+		for i, p := range s.probabilities {
+			if u < p {
+				return i
+			}
+			u = u - p
+		}
+		return len(s.probabilities) - 1
+	}
+	c := s.params.C
+	if c <= 0.0 {
+		c = 1.0
+	}
+	best := 0
+	best_score := math.Inf(-1)
+	for i, n := range s.counts {
+		if n == 0 {
+			return i
+		}
+		score := s.rewards[i]/float64(n) + c*math.Sqrt(2.0*math.Log(float64(t))/float64(n))
+		if score > best_score {
+			best = i
+			best_score = score
+		}
+	}
+	return best
+}
+
+func updateSelector(s *selector, k int, reward float64) {
+	s.counts[k]++
+	if s.selector != AdaptivePursuit {
+		s.rewards[k] = s.rewards[k] + reward
+		return
+	}
+	n := float64(len(s.probabilities))
+	p_min := s.params.P_min
+	if p_min <= 0.0 {
+		p_min = 0.1 / n
+	}
+	p_max := 1.0 - (n-1.0)*p_min
+	alpha := defaultRate(s.params.Alpha)
+	beta := defaultRate(s.params.Beta)
+	s.rewards[k] = s.rewards[k] + alpha*(reward-s.rewards[k])
+	best := 0
+	for i, q := range s.rewards {
+		if q > s.rewards[best] {
+			best = i
+		}
+	}
+	for i := range s.probabilities {
+		target := p_min
+		if i == best {
+			target = p_max
+		}
+		s.probabilities[i] = s.probabilities[i] + beta*(target-s.probabilities[i])
+	}
+}
+
+func defaultRate(rate float64) float64 {
+	if rate > 0.0 {
+		return rate
+	}
+	return 0.8
+}
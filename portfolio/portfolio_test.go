@@ -0,0 +1,77 @@
+package portfolio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/applied-math-coding/heuristic/abc"
+	"github.com/applied-math-coding/heuristic/de"
+	"github.com/applied-math-coding/heuristic/lus"
+	"github.com/applied-math-coding/heuristic/pso"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPortfolio(t *testing.T) {
+	n := 5
+	b_low := mat.NewVecDense(n, nil)
+	b_up := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		b_low.SetVec(i, -5.12)
+		b_up.SetVec(i, 5.12)
+	}
+	rastrigin := func(x mat.Vector) float64 {
+		res := 10.0 * float64(x.Len())
+		for i := 0; i < x.Len(); i++ {
+			res = res + math.Pow(x.AtVec(i), 2.0) - 10.0*math.Cos(2.0*math.Pi*x.AtVec(i))
+		}
+		return res
+	}
+	calls := 0
+	counted := func(x mat.Vector) float64 {
+		calls++
+		return rastrigin(x)
+	}
+	members := []*Member{
+		PSO(pso.Params{N_particles: 20, Inertia: pso.Constriction, Phi_p: 2.05, Phi_g: 2.05}),
+		DE(de.Params{N_agents: 20, F: 0.5, CR: 0.9}),
+		ABC(abc.Params{N_bees: 20, Abandon_limit: 50}),
+		LUS(lus.Params{Precision: 1e-6})}
+	for _, selector := range []Selector{UCB, AdaptivePursuit} {
+		params := &Params{
+			Max_evaluations:   40000,
+			Slice_evaluations: 1000,
+			Selector:          selector,
+			Migration:         true}
+		calls = 0
+		res := Optimize(counted, b_low, b_up, members, params)
+		t.Log(selector, res.Value, res.Contributor, res.Selections, res.Evaluations)
+		spent := 0
+		for _, e := range res.Evaluations {
+			spent = spent + e
+		}
+		if spent != calls || calls > params.Max_evaluations || rastrigin(res.X) != res.Value {
+			t.Fatal("budget exceeded", spent, calls)
+		}
+	}
+}
+
+func TestPortfolioBounds(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{0.0, 0.0})
+	b_up := mat.NewVecDense(2, []float64{1.0, 1.0})
+	f := func(x mat.Vector) float64 {
+		return -x.AtVec(0) - x.AtVec(1)
+	}
+	members := []*Member{DE(de.Params{N_agents: 20, F: 0.9, CR: 0.9})}
+	res := Optimize(f, b_low, b_up, members, &Params{Max_evaluations: 4000, Migration: true})
+	t.Log(res.X, res.Value)
+	if !inBounds(res.X, b_low, b_up) {
+		t.Fatal("result outside the bounds", res.X)
+	}
+}
+
+func TestReward(t *testing.T) {
+	if reward(math.Inf(1), 5.0) != 1.0 || reward(2.0, 2.0) != 0.0 || reward(4.0, 3.0) != 0.25 {
+		t.Fatal(reward(math.Inf(1), 5.0), reward(2.0, 2.0), reward(4.0, 3.0))
+	}
+}
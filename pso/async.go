@@ -0,0 +1,89 @@
+package pso
+
+import (
+	"math"
+	"sync"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// swarm state shared between the particles of OptimizeAsync
+type shared = struct {
+	mutex   sync.Mutex
+	p       []*mat.VecDense
+	value_p []float64
+	g       *mat.VecDense
+	value_g float64
+}
+
+// OptimizeAsync runs every particle in its own goroutine. A particle moves as soon as its own evaluation
+// has returned, using the best positions known at that moment, so there is no barrier between iterations.
+// This pays off if evaluation times of f differ. f must be safe for concurrent use.
+// N_workers limits the number of concurrent evaluations (0 means one per particle).
+// Topologies are wired once at the start and the velocity update of the Standard variant is used.
+func OptimizeAsync(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
+	r := common.GetNewRand()
+	s := &shared{}
+	s.p = initParticles(b_low, b_up, params.N_particles, params.Init)
+	v := initVelocity(b_low, b_up, params.N_particles)
+	x := initParticlePositions(s.p)
+	s.value_p = evaluateParticles(f, s.p)
+	best := common.SelectBest(s.value_p, 1)[0]
+	s.g = mat.VecDenseCopyOf(s.p[best])
+	s.value_g = s.value_p[best]
+	neighbours := updateNeighbours(nil, 0, true, params, r)
+	n_workers := params.N_workers
+	if n_workers <= 0 {
+		n_workers = params.N_particles
+	}
+	workers := make(chan struct{}, n_workers)
+	var wg sync.WaitGroup
+	for i := 0; i < params.N_particles; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			moveAsync(i, f, x[i], v[i], s, neighbours, workers, b_low, b_up, params)
+		}(i)
+	}
+	wg.Wait()
+	return s.g
+}
+
+func moveAsync(i int, f common.Target, x *mat.VecDense, v *mat.VecDense, s *shared, neighbours [][]int,
+	workers chan struct{}, b_low mat.Vector, b_up mat.Vector, params *Params) {
+	r := common.GetNewRand()
+	n := x.Len()
+	p := mat.NewVecDense(n, nil)
+	l := mat.NewVecDense(n, nil)
+	z := 0.1 + 0.8*r.Float64()
+	for iter := 0; iter < params.Max_iter; iter++ {
+		z = nextChaos(z)
+		t := float64(iter) / math.Max(float64(params.Max_iter-1), 1.0)
+		s.mutex.Lock()
+		p.CopyVec(s.p[i])
+		if params.Topology == Global {
+			l.CopyVec(s.g)
+		} else {
+			l.CopyVec(localBest(neighbours[i], s.p, s.value_p))
+		}
+		s.mutex.Unlock()
+		updateVelocity(v, x, p, l, computeCoefficients(t, z, params, r), params.Scalar_random)
+		clampVelocity(v, b_low, b_up, params.V_max)
+		updateParticlePositions(x, v, learningRate(params), b_low, b_up, params.Boundary)
+		workers <- struct{}{}
+		value_x := f(x)
+		<-workers
+		s.mutex.Lock()
+		if value_x < s.value_p[i] {
+			s.p[i].CopyVec(x)
+			s.value_p[i] = value_x
+		}
+		if value_x < s.value_g {
+			s.value_g = value_x
+			s.g.CopyVec(x)
+		}
+		s.mutex.Unlock()
+	}
+}
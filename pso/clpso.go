@@ -0,0 +1,71 @@
+package pso
+
+import (
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Comprehensive learning PSO (Liang et al. 2006): each dimension of a particle learns from the personal best
+// of a particle chosen by tournament. The choice is refreshed after Refresh_gap iterations without improvement.
+
+func refreshGap(params *Params) int {
+	if params.Refresh_gap <= 0 {
+		return 7
+	}
+	return params.Refresh_gap
+}
+
+// learning probability of particle i, ranging from 0.05 to 0.5 over the swarm
+func learningProbability(i int, n_particles int) float64 {
+	if n_particles < 2 {
+		return 0.05
+	}
+	return 0.05 + 0.45*(math.Exp(10.0*float64(i)/float64(n_particles-1))-1.0)/(math.Exp(10.0)-1.0)
+}
+
+// chooseExemplars returns for each dimension the index of the particle whose personal best particle i learns from
+func chooseExemplars(i int, n int, value_p []float64, pc float64, r *rand.Rand) []int {
+	res := make([]int, n)
+	learns := false
+	for d := 0; d < n; d++ {
+		res[d] = i
+		if len(value_p) > 1 && r.Float64() < pc {
+			res[d] = tournament(i, value_p, r)
+			learns = true
+		}
+	}
+	if !learns && len(value_p) > 1 {
+		res[r.Intn(n)] = tournament(i, value_p, r)
+	}
+	return res
+}
+
+// the better of two random particles other than i
+func tournament(i int, value_p []float64, r *rand.Rand) int {
+	other := func() int {
+		j := r.Intn(len(value_p) - 1)
+		if j >= i {
+			j++
+		}
+		return j
+	}
+	a, b := other(), other()
+	if value_p[b] < value_p[a] {
+		return b
+	}
+	return a
+}
+
+func updateComprehensiveVelocity(v *mat.VecDense, x *mat.VecDense, p []*mat.VecDense, exemplars []int,
+	c *coefficients, r *rand.Rand) {
+	phi := c.phi_p
+	if phi <= 0.0 {
+		phi = 1.49445
+	}
+	for d := 0; d < v.Len(); d++ {
+		v_d := c.omega*v.AtVec(d) + phi*r.Float64()*(p[exemplars[d]].AtVec(d)-x.AtVec(d))
+		v.SetVec(d, c.chi*v_d)
+	}
+}
@@ -0,0 +1,138 @@
+package pso
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type BinaryTarget = func(x []bool) float64
+type IntegerTarget = func(x []int) float64
+
+// Transfer maps the velocity of binary PSO onto a probability.
+type Transfer int
+
+const (
+	SShaped Transfer = iota // sigmoid(v) is the probability of the bit being set (Kennedy, Eberhart 1997)
+	VShaped                 // |tanh(v)| is the probability of flipping the bit (Mirjalili, Lewis 2013)
+)
+
+// OptimizeBinary searches a minimum of f over bitstrings of length n, e.g. for feature selection.
+// The velocity update uses the same coefficients, inertia schedules and topologies as Optimize.
+// Here V_max is an absolute limit of the velocity and defaults to 4.
+// Variant is ignored, the Standard velocity update is always used.
+func OptimizeBinary(f BinaryTarget, n int, params *Params) []bool {
+	r := common.GetNewRand()
+	v_max := params.V_max
+	if v_max <= 0.0 {
+		v_max = 4.0
+	}
+	b_low := mat.NewVecDense(n, nil)
+	b_up := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		b_up.SetVec(i, 1.0)
+	}
+	target := func(x mat.Vector) float64 {
+		return f(toBits(x))
+	}
+	p := make([]*mat.VecDense, params.N_particles)
+	for i := range p {
+		p[i] = mat.NewVecDense(n, nil)
+		for d := 0; d < n; d++ {
+			p[i].SetVec(d, float64(r.Intn(2)))
+		}
+	}
+	v := initVelocity(b_low, b_up, params.N_particles)
+	x := initParticlePositions(p)
+	value_p := evaluateParticles(target, p)
+	g := mat.NewVecDense(n, nil)
+	g.CopyVec(localBest(common.Indices(params.N_particles), p, value_p))
+	value_g := target(g)
+	var neighbours [][]int
+	improved := true
+	z := 0.1 + 0.8*r.Float64()
+	for iter := 0; iter < params.Max_iter; iter++ {
+		neighbours = updateNeighbours(neighbours, iter, improved, params, r)
+		improved = false
+		z = nextChaos(z)
+		t := float64(iter) / math.Max(float64(params.Max_iter-1), 1.0)
+		for i := 0; i < params.N_particles; i++ {
+			l := g
+			if params.Topology != Global {
+				l = localBest(neighbours[i], p, value_p)
+			}
+			updateVelocity(v[i], x[i], p[i], l, computeCoefficients(t, z, params, r), params.Scalar_random)
+			common.ApplyElementwise(v[i], func(e float64, idx int) float64 {
+				return math.Min(math.Max(-v_max, e), v_max)
+			})
+			applyTransfer(x[i], v[i], params.Transfer, r)
+			value_x := target(x[i])
+			if value_x < value_p[i] {
+				p[i].CopyVec(x[i])
+				value_p[i] = value_x
+			}
+			if value_x < value_g {
+				value_g = value_x
+				g.CopyVec(x[i])
+				improved = true
+			}
+		}
+	}
+	return toBits(g)
+}
+
+// OptimizeInteger searches a minimum of f over integer vectors within b_low and b_up (inclusive).
+// The swarm moves in the continuous relaxation where each integer owns an interval of width 1
+// and positions are rounded for evaluation, so all options of Optimize apply.
+func OptimizeInteger(f IntegerTarget, b_low []int, b_up []int, params *Params) []int {
+	n := len(b_low)
+	relaxed_low := mat.NewVecDense(n, nil)
+	relaxed_up := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		relaxed_low.SetVec(i, float64(b_low[i])-0.5)
+		relaxed_up.SetVec(i, float64(b_up[i])+0.5)
+	}
+	round := func(x mat.Vector) []int {
+		res := make([]int, n)
+		for i := 0; i < n; i++ {
+			k := int(math.Round(x.AtVec(i)))
+			if k < b_low[i] {
+				k = b_low[i]
+			}
+			if k > b_up[i] {
+				k = b_up[i]
+			}
+			res[i] = k
+		}
+		return res
+	}
+	best := Optimize(func(x mat.Vector) float64 {
+		return f(round(x))
+	}, relaxed_low, relaxed_up, params)
+	return round(best)
+}
+
+func applyTransfer(x *mat.VecDense, v *mat.VecDense, transfer Transfer, r *rand.Rand) {
+	for d := 0; d < x.Len(); d++ {
+		if transfer == VShaped {
+			if r.Float64() < math.Abs(math.Tanh(v.AtVec(d))) {
+				x.SetVec(d, 1.0-x.AtVec(d))
+			}
+		} else if r.Float64() < 1.0/(1.0+math.Exp(-v.AtVec(d))) {
+			x.SetVec(d, 1.0)
+		} else {
+			x.SetVec(d, 0.0)
+		}
+	}
+}
+
+func toBits(x mat.Vector) []bool {
+	res := make([]bool, x.Len())
+	for i := range res {
+		res[i] = x.AtVec(i) > 0.5
+	}
+	return res
+}
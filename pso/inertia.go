@@ -0,0 +1,113 @@
+package pso
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+type Inertia int
+
+const (
+	ConstantInertia  Inertia = iota // Omega throughout the run
+	LinearInertia                   // decreases linearly from Omega to Omega_min
+	NonlinearInertia                // Omega_min + (Omega - Omega_min) * (1 - t)^Inertia_exponent
+	ChaoticInertia                  // linear decrease plus a logistic-map term (Feng et al. 2007)
+	RandomInertia                   // 0.5 + r / 2 with r uniform in [0, 1) (Eberhart, Shi 2001)
+	Constriction                    // Clerc's constriction factor, requires Phi_p + Phi_g > 4
+)
+
+// coefficients of the velocity update at a certain iteration
+type coefficients = struct {
+	omega float64
+	phi_p float64
+	phi_g float64
+	chi   float64 // multiplies the whole new velocity
+}
+
+// computeCoefficients evaluates the schedules at progress t in [0, 1]. z is the state of the logistic map.
+func computeCoefficients(t float64, z float64, params *Params, r *rand.Rand) *coefficients {
+	phi_p, phi_g := accelerations(t, params)
+	c := &coefficients{omega: params.Omega, phi_p: phi_p, phi_g: phi_g, chi: 1.0}
+	switch params.Inertia {
+	case LinearInertia:
+		c.omega = params.Omega - (params.Omega-params.Omega_min)*t
+	case NonlinearInertia:
+		exponent := params.Inertia_exponent
+		if exponent <= 0.0 {
+			exponent = 1.2
+		}
+		c.omega = params.Omega_min + (params.Omega-params.Omega_min)*math.Pow(1.0-t, exponent)
+	case ChaoticInertia:
+		c.omega = (params.Omega-params.Omega_min)*(1.0-t) + params.Omega_min*z
+	case RandomInertia:
+		c.omega = 0.5 + r.Float64()/2.0
+	case Constriction:
+		c.omega = 1.0
+		c.chi = constrictionFactor(phi_p + phi_g)
+	}
+	return c
+}
+
+// time-varying acceleration coefficients (Ratnaweera et al. 2004) move from Phi_p, Phi_g
+// to Phi_p_final, Phi_g_final which default to 0.5 and 2.5
+func accelerations(t float64, params *Params) (float64, float64) {
+	if !params.TVAC {
+		return params.Phi_p, params.Phi_g
+	}
+	phi_p_final, phi_g_final := finalAccelerations(params)
+	return params.Phi_p + (phi_p_final-params.Phi_p)*t, params.Phi_g + (phi_g_final-params.Phi_g)*t
+}
+
+func finalAccelerations(params *Params) (float64, float64) {
+	phi_p_final := params.Phi_p_final
+	if phi_p_final <= 0.0 {
+		phi_p_final = 0.5
+	}
+	phi_g_final := params.Phi_g_final
+	if phi_g_final <= 0.0 {
+		phi_g_final = 2.5
+	}
+	return phi_p_final, phi_g_final
+}
+
+func constrictionFactor(phi float64) float64 {
+	if phi <= 4.0 {
+		return 1.0
+	}
+	return 2.0 / math.Abs(2.0-phi-math.Sqrt(phi*phi-4.0*phi))
+}
+
+func nextChaos(z float64) float64 {
+	return 4.0 * z * (1.0 - z)
+}
+
+// Validate checks whether params lie in the region where the swarm converges.
+// For inertia weights the order-2 stability region |omega| < 1, 0 < Phi_p + Phi_g < 24 (1 - omega^2) / (7 - 5 omega)
+// (Poli 2009) is checked for the coefficients reached at the end of the run, assuming a LearningRate of 1.
+// Decreasing schedules may start outside the region in order to explore.
+// Constriction requires Phi_p + Phi_g > 4.
+func Validate(params *Params) error {
+	if params.N_particles <= 0 {
+		return errors.New("pso: N_particles must be positive")
+	}
+	phi_p, phi_g := accelerations(1.0, params)
+	phi := phi_p + phi_g
+	omega := params.Omega
+	switch params.Inertia {
+	case LinearInertia, NonlinearInertia, ChaoticInertia:
+		omega = params.Omega_min
+	case RandomInertia:
+		omega = 0.75
+	case Constriction:
+		if phi <= 4.0 {
+			return fmt.Errorf("pso: constriction requires Phi_p + Phi_g > 4, got %v", phi)
+		}
+		return nil
+	}
+	if math.Abs(omega) >= 1.0 || phi <= 0.0 || phi >= 24.0*(1.0-omega*omega)/(7.0-5.0*omega) {
+		return fmt.Errorf("pso: omega %v with Phi_p + Phi_g = %v is outside the stability region", omega, phi)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package pso
+
+import (
+	"math"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Optimum = common.Optimum
+
+// FindOptima runs a species-based PSO (Li 2004) in order to locate several local optima in one run.
+// In each iteration the best particles not closer than Niche_radius to a better one become species seeds
+// and every particle follows the seed of its species instead of the global best.
+// If Species_size is positive, members exceeding it are re-initialised randomly which keeps
+// particles exploring other regions (anti-convergence).
+// The returned seeds are distinct by Niche_radius and sorted by value.
+func FindOptima(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) []Optimum {
+	r := common.GetNewRand()
+	p := initParticles(b_low, b_up, params.N_particles, params.Init)
+	v := initVelocity(b_low, b_up, params.N_particles)
+	x := initParticlePositions(p)
+	value_p := evaluateParticles(f, p)
+	z := 0.1 + 0.8*r.Float64()
+	for iter := 0; iter < params.Max_iter; iter++ {
+		t := float64(iter) / math.Max(float64(params.Max_iter-1), 1.0)
+		z = nextChaos(z)
+		seeds, species := common.DetermineSpecies(vectors(p), value_p, params.Niche_radius)
+		if params.Species_size > 0 {
+			// surplus members are re-initialised randomly
+			for _, i := range common.SurplusMembers(seeds, species, value_p, params.Species_size) {
+				p[i] = common.RandomDataInBounds(b_low, b_up)
+				x[i].CopyVec(p[i])
+				v[i].Zero()
+				value_p[i] = f(p[i])
+			}
+		}
+		for i := 0; i < params.N_particles; i++ {
+			l := p[species[i]]
+			updateVelocity(v[i], x[i], p[i], l, computeCoefficients(t, z, params, r), params.Scalar_random)
+			clampVelocity(v[i], b_low, b_up, params.V_max)
+			updateParticlePositions(x[i], v[i], learningRate(params), b_low, b_up, params.Boundary)
+			value_x := f(x[i])
+			if value_x < value_p[i] {
+				p[i].CopyVec(x[i])
+				value_p[i] = value_x
+			}
+		}
+	}
+	return common.SpeciesSeeds(vectors(p), value_p, params.Niche_radius)
+}
+
+func vectors(p []*mat.VecDense) []mat.Vector {
+	res := make([]mat.Vector, len(p))
+	for i, e := range p {
+		res[i] = e
+	}
+	return res
+}
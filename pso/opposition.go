@@ -0,0 +1,61 @@
+package pso
+
+import (
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// opposeParticles evaluates the opposite points of the positions with respect to the given bounds and keeps
+// the best len(x) of both sets (Wang et al. 2007). A particle whose position is dropped moves onto a kept
+// opposite point, its own one if possible, and keeps its velocity. Personal bests only change on improvement.
+func opposeParticles(f common.Target, x []*mat.VecDense, value_x []float64, p []*mat.VecDense,
+	value_p []float64, b_low mat.Vector, b_up mat.Vector) {
+	n := len(x)
+	opposites := make([]*mat.VecDense, n)
+	all_values := append([]float64(nil), value_x...)
+	for i, e := range x {
+		opposites[i] = common.Opposite(e, b_low, b_up)
+		all_values = append(all_values, f(opposites[i]))
+	}
+	kept := make([]bool, 2*n)
+	for _, i := range common.SelectBest(all_values, n) {
+		kept[i] = true
+	}
+	// kept opposites whose own particle keeps its position go to the other dropped particles
+	spare := make([]int, 0)
+	for i := 0; i < n; i++ {
+		if kept[n+i] && kept[i] {
+			spare = append(spare, i)
+		}
+	}
+	for i := 0; i < n; i++ {
+		if kept[i] {
+			continue
+		}
+		j := i
+		if !kept[n+i] {
+			j, spare = spare[0], spare[1:]
+		}
+		x[i].CopyVec(opposites[j])
+		value_x[i] = all_values[n+j]
+		if value_x[i] < value_p[i] {
+			p[i].CopyVec(x[i])
+			value_p[i] = value_x[i]
+		}
+	}
+}
+
+// jumpGeneration applies opposeParticles within the dynamic bounds of the current positions
+// with probability Jumping_rate
+func jumpGeneration(f common.Target, x []*mat.VecDense, value_x []float64, p []*mat.VecDense,
+	value_p []float64, params *Params, r *rand.Rand) bool {
+	if !params.Opposition || r.Float64() >= common.JumpingRate(params.Jumping_rate) {
+		return false
+	}
+	b_low, b_up := common.DynamicBounds(vectors(x))
+	opposeParticles(f, x, value_x, p, value_p, b_low, b_up)
+	return true
+}
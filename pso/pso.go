@@ -4,65 +4,197 @@ import (
 	"math"
 
 	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/sampling"
 
 	"gonum.org/v1/gonum/mat"
 )
 
+// A LearningRate of 0 is treated as 1, which is the standard PSO position update.
 type Params = struct {
-	Omega        float64
-	Phi_p        float64
-	Phi_g        float64
-	N_particles  int
-	LearningRate float64
-	Max_iter     int
+	Omega            float64
+	Phi_p            float64
+	Phi_g            float64
+	N_particles      int
+	LearningRate     float64
+	Max_iter         int
+	Topology         Topology
+	Neighbours       int // k of Ring and Random
+	Rewire_every     int // iterations between re-wiring a Random topology
+	Inertia          Inertia
+	Omega_min        float64 // final inertia of decreasing schedules
+	Inertia_exponent float64 // NonlinearInertia, defaults to 1.2
+	TVAC             bool    // time-varying acceleration coefficients
+	Phi_p_final      float64 // TVAC, defaults to 0.5
+	Phi_g_final      float64 // TVAC, defaults to 2.5
+	Scalar_random    bool    // one random factor for all dimensions instead of one per dimension
+	V_max            float64 // velocity limit relative to b_up - b_low, 0 means unlimited
+	Boundary         Boundary
+	Variant          Variant
+	Beta             float64          // contraction-expansion of Quantum, 0 means decreasing from 1.0 to 0.5
+	Refresh_gap      int              // ComprehensiveLearning
+	Niche_radius     float64          // FindOptima, minimal distance between distinct optima
+	Species_size     int              // FindOptima, maximal particles per species, 0 means unlimited
+	Transfer         Transfer         // OptimizeBinary
+	N_workers        int              // OptimizeAsync, concurrent evaluations, 0 means one per particle
+	Opposition       bool             // Optimize, opposition-based initialisation and generation jumps keeping the better half of positions and opposites
+	Jumping_rate     float64          // Opposition, probability of a generation jump per iteration, defaults to 0.3
+	Init             sampling.Sampler // initial positions, nil means uniform
 }
 
+// Boundary determines what happens to the velocity of a particle which is stopped at a bound.
+type Boundary int
+
+const (
+	KeepVelocity Boundary = iota
+	ZeroVelocity
+	ReflectVelocity
+)
+
 func Optimize(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
+	r := common.GetNewRand()
 	g := common.RandomDataInBounds(b_low, b_up)
-	p := initParticles(b_low, b_up, params.N_particles)
+	p := initParticles(b_low, b_up, params.N_particles, params.Init)
 	v := initVelocity(b_low, b_up, params.N_particles)
 	x := initParticlePositions(p)
 	value_g := f(g)
+	value_p := evaluateParticles(f, p)
+	value_x := append([]float64(nil), value_p...)
+	if params.Opposition {
+		opposeParticles(f, x, value_x, p, value_p, b_low, b_up)
+		value_g = updateGlobalBest(g, value_g, p, value_p)
+	}
+	exemplars := make([][]int, params.N_particles)
+	stale := make([]int, params.N_particles)
+	var neighbours [][]int
+	improved := true
+	z := 0.1 + 0.8*r.Float64()
 	for iter := 0; iter < params.Max_iter; iter++ {
+		neighbours = updateNeighbours(neighbours, iter, improved, params, r)
+		improved = false
+		z = nextChaos(z)
+		t := float64(iter) / math.Max(float64(params.Max_iter-1), 1.0)
+		var mbest *mat.VecDense
+		if params.Variant == Quantum {
+			mbest = meanBest(p)
+		}
 		for i := 0; i < params.N_particles; i++ {
-			updateVelocity(v[i], x[i], p[i], g, params)
-			updateParticlePositions(x[i], v[i], params.LearningRate, b_low, b_up)
-			value_x := f(x[i])
-			if value_x < f(p[i]) {
+			l := g
+			if params.Topology != Global {
+				l = localBest(neighbours[i], p, value_p)
+			}
+			switch params.Variant {
+			case BareBones:
+				moveBareBones(x[i], p[i], l, r)
+				clampPosition(x[i], b_low, b_up)
+			case Quantum:
+				moveQuantum(x[i], p[i], l, mbest, contractionExpansion(t, params), r)
+				clampPosition(x[i], b_low, b_up)
+			case ComprehensiveLearning:
+				if exemplars[i] == nil || stale[i] >= refreshGap(params) {
+					pc := learningProbability(i, params.N_particles)
+					exemplars[i] = chooseExemplars(i, b_low.Len(), value_p, pc, r)
+					stale[i] = 0
+				}
+				updateComprehensiveVelocity(v[i], x[i], p, exemplars[i], computeCoefficients(t, z, params, r), r)
+				clampVelocity(v[i], b_low, b_up, params.V_max)
+				updateParticlePositions(x[i], v[i], learningRate(params), b_low, b_up, params.Boundary)
+			default:
+				updateVelocity(v[i], x[i], p[i], l, computeCoefficients(t, z, params, r), params.Scalar_random)
+				clampVelocity(v[i], b_low, b_up, params.V_max)
+				updateParticlePositions(x[i], v[i], learningRate(params), b_low, b_up, params.Boundary)
+			}
+			value_x[i] = f(x[i])
+			if value_x[i] < value_p[i] {
 				p[i].CopyVec(x[i])
+				value_p[i] = value_x[i]
+				stale[i] = 0
+			} else {
+				stale[i]++
 			}
-			if value_x < value_g {
-				value_g = value_x
+			if value_x[i] < value_g {
+				value_g = value_x[i]
 				g.CopyVec(x[i])
+				improved = true
 			}
 		}
+		if jumpGeneration(f, x, value_x, p, value_p, params, r) {
+			value_g = updateGlobalBest(g, value_g, p, value_p)
+		}
 	}
 	return g
 }
 
+// copies the best personal best into g if it is better and returns the new value of g
+func updateGlobalBest(g *mat.VecDense, value_g float64, p []*mat.VecDense, value_p []float64) float64 {
+	for i, e := range p {
+		if value_p[i] < value_g {
+			value_g = value_p[i]
+			g.CopyVec(e)
+		}
+	}
+	return value_g
+}
+
+func learningRate(params *Params) float64 {
+	if params.LearningRate == 0.0 {
+		return 1.0
+	}
+	return params.LearningRate
+}
+
+func evaluateParticles(f common.Target, p []*mat.VecDense) []float64 {
+	res := make([]float64, len(p))
+	for i, e := range p {
+		res[i] = f(e)
+	}
+	return res
+}
+
 func updateParticlePositions(x *mat.VecDense, v *mat.VecDense, learningRate float64,
-	b_low mat.Vector, b_up mat.Vector) {
+	b_low mat.Vector, b_up mat.Vector, boundary Boundary) {
 	x.AddScaledVec(x, learningRate, v)
 	common.ApplyElementwise(x, func(e float64, idx int) float64 {
+		if e >= b_low.AtVec(idx) && e <= b_up.AtVec(idx) {
+			return e
+		}
+		switch boundary {
+		case ZeroVelocity:
+			v.SetVec(idx, 0.0)
+		case ReflectVelocity:
+			v.SetVec(idx, -v.AtVec(idx))
+		}
 		return math.Min(math.Max(b_low.AtVec(idx), e), b_up.AtVec(idx))
 	})
 }
 
+// g is the best position known to the particle's neighbourhood
 func updateVelocity(v *mat.VecDense, x *mat.VecDense,
-	p *mat.VecDense, g *mat.VecDense, params *Params) {
+	p *mat.VecDense, g *mat.VecDense, c *coefficients, scalar_random bool) {
 	n := g.Len()
 	r := common.GetNewRand()
 	r_p := r.Float64()
 	r_g := r.Float64()
-	v.ScaleVec(params.Omega, v)
-	diff_p_x := mat.NewVecDense(n, nil)
-	diff_p_x.SubVec(p, x)
-	diff_p_x.ScaleVec(r_p*params.Phi_p, diff_p_x)
-	v.AddVec(v, diff_p_x)
-	diff_g_x := mat.NewVecDense(n, nil)
-	diff_g_x.SubVec(g, x)
-	diff_g_x.ScaleVec(r_g*params.Phi_g, diff_g_x)
-	v.AddVec(v, diff_g_x)
+	for i := 0; i < n; i++ {
+		if !scalar_random {
+			r_p = r.Float64()
+			r_g = r.Float64()
+		}
+		v_i := c.omega*v.AtVec(i) +
+			r_p*c.phi_p*(p.AtVec(i)-x.AtVec(i)) +
+			r_g*c.phi_g*(g.AtVec(i)-x.AtVec(i))
+		v.SetVec(i, c.chi*v_i)
+	}
+}
+
+// limits each component to v_max * (b_up - b_low)
+func clampVelocity(v *mat.VecDense, b_low mat.Vector, b_up mat.Vector, v_max float64) {
+	if v_max <= 0.0 {
+		return
+	}
+	common.ApplyElementwise(v, func(e float64, idx int) float64 {
+		limit := v_max * math.Abs(b_up.AtVec(idx)-b_low.AtVec(idx))
+		return math.Min(math.Max(-limit, e), limit)
+	})
 }
 
 func initParticlePositions(p []*mat.VecDense) []*mat.VecDense {
@@ -92,10 +224,6 @@ func initVelocity(b_low mat.Vector, b_up mat.Vector, n_particles int) []*mat.Vec
 	return v
 }
 
-func initParticles(b_low mat.Vector, b_up mat.Vector, n_particles int) []*mat.VecDense {
-	p := make([]*mat.VecDense, n_particles)
-	for i := 0; i < n_particles; i++ {
-		p[i] = common.RandomDataInBounds(b_low, b_up)
-	}
-	return p
+func initParticles(b_low mat.Vector, b_up mat.Vector, n_particles int, init sampling.Sampler) []*mat.VecDense {
+	return sampling.Sample(init, b_low, b_up, n_particles)
 }
@@ -2,7 +2,13 @@ package pso
 
 import (
 	"math"
+	"math/rand"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/sampling"
 
 	"gonum.org/v1/gonum/mat"
 )
@@ -39,7 +45,7 @@ func TestInitVelocity(t *testing.T) {
 func TestInitParticles(t *testing.T) {
 	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
 	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
-	p := initParticles(b_low, b_up, 3)
+	p := initParticles(b_low, b_up, 3, nil)
 	t.Log(p[0])
 	t.Log(p[1])
 	t.Log(p[2])
@@ -48,9 +54,368 @@ func TestInitParticles(t *testing.T) {
 func TestParticlePositions(t *testing.T) {
 	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
 	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
-	p := initParticles(b_low, b_up, 3)
+	p := initParticles(b_low, b_up, 3, nil)
 	x := initParticlePositions(p)
 	t.Log(x[0])
 	t.Log(x[1])
 	t.Log(x[2])
 }
+
+func TestPsoTopologies(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-5.12, -5.12})
+	b_up := mat.NewVecDense(2, []float64{5.12, 5.12})
+	rastrigin := func(x mat.Vector) float64 {
+		res := 10.0 * float64(x.Len())
+		for i := 0; i < x.Len(); i++ {
+			res = res + math.Pow(x.AtVec(i), 2.0) - 10.0*math.Cos(2.0*math.Pi*x.AtVec(i))
+		}
+		return res
+	}
+	for _, topology := range []Topology{Global, Ring, VonNeumann, Random, Dynamic} {
+		params := &Params{
+			N_particles:  50,
+			Max_iter:     100,
+			Omega:        0.7,
+			Phi_p:        1.5,
+			Phi_g:        1.5,
+			LearningRate: 1.0,
+			Topology:     topology,
+			Neighbours:   2}
+		min := Optimize(rastrigin, b_low, b_up, params)
+		t.Log(topology, min, rastrigin(min))
+	}
+}
+
+func TestPsoInertia(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	variants := []*Params{
+		{Inertia: ConstantInertia, Omega: 0.7, Phi_p: 1.4, Phi_g: 1.4},
+		{Inertia: LinearInertia, Omega: 0.9, Omega_min: 0.4, Phi_p: 1.4, Phi_g: 1.4},
+		{Inertia: NonlinearInertia, Omega: 0.9, Omega_min: 0.4, Phi_p: 1.4, Phi_g: 1.4},
+		{Inertia: ChaoticInertia, Omega: 0.9, Omega_min: 0.4, Phi_p: 1.4, Phi_g: 1.4},
+		{Inertia: RandomInertia, Phi_p: 1.4, Phi_g: 1.4},
+		{Inertia: Constriction, Phi_p: 2.05, Phi_g: 2.05},
+		{Inertia: LinearInertia, Omega: 0.9, Omega_min: 0.4, Phi_p: 2.5, Phi_g: 0.5, TVAC: true}}
+	for _, params := range variants {
+		params.N_particles = 50
+		params.Max_iter = 100
+		if errValidate := Validate(params); errValidate != nil {
+			t.Fatal(errValidate)
+		}
+		min := Optimize(f, b_low, b_up, params)
+		t.Log(params.Inertia, min, f(min))
+	}
+	if Validate(&Params{N_particles: 50, Omega: 1.0, Phi_p: 2.0, Phi_g: 2.0}) == nil {
+		t.Fatal("TestPsoInertia accepts unstable parameters")
+	}
+}
+
+func TestPsoVelocity(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	for _, boundary := range []Boundary{KeepVelocity, ZeroVelocity, ReflectVelocity} {
+		params := &Params{
+			N_particles: 50,
+			Max_iter:    100,
+			Omega:       0.7,
+			Phi_p:       1.4,
+			Phi_g:       1.4,
+			V_max:       0.2,
+			Boundary:    boundary}
+		min := Optimize(f, b_low, b_up, params)
+		t.Log(boundary, min, f(min))
+	}
+	v := mat.NewVecDense(2, []float64{15.0, -1.0})
+	clampVelocity(v, b_low, b_up, 0.2)
+	if v.AtVec(0) != 4.0 || v.AtVec(1) != -1.0 {
+		t.Fatal("TestPsoVelocity fails to clamp")
+	}
+}
+
+func TestPsoVariants(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	for _, variant := range []Variant{BareBones, Quantum} {
+		params := &Params{N_particles: 50, Max_iter: 100, Variant: variant}
+		min := Optimize(f, b_low, b_up, params)
+		t.Log(variant, min, f(min))
+	}
+}
+
+func TestPsoComprehensiveLearning(t *testing.T) {
+	n := 10
+	b_low := mat.NewVecDense(n, nil)
+	b_up := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		b_low.SetVec(i, -5.12)
+		b_up.SetVec(i, 5.12)
+	}
+	rastrigin := func(x mat.Vector) float64 {
+		res := 10.0 * float64(x.Len())
+		for i := 0; i < x.Len(); i++ {
+			res = res + math.Pow(x.AtVec(i), 2.0) - 10.0*math.Cos(2.0*math.Pi*x.AtVec(i))
+		}
+		return res
+	}
+	params := &Params{
+		N_particles: 40,
+		Max_iter:    500,
+		Variant:     ComprehensiveLearning,
+		Inertia:     LinearInertia,
+		Omega:       0.9,
+		Omega_min:   0.4,
+		V_max:       0.2}
+	min := Optimize(rastrigin, b_low, b_up, params)
+	t.Log(min)
+	t.Log(rastrigin(min))
+}
+
+func TestPsoOpposition(t *testing.T) {
+	n := 10
+	b_low := mat.NewVecDense(n, nil)
+	b_up := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		b_low.SetVec(i, -5.0)
+		b_up.SetVec(i, 10.0)
+	}
+	f := func(x mat.Vector) float64 {
+		res := 0.0
+		for i := 0; i < x.Len(); i++ {
+			res = res + math.Pow(x.AtVec(i)-7.0, 2.0)
+		}
+		return res
+	}
+	for _, opposition := range []bool{false, true} {
+		params := &Params{
+			N_particles: 20,
+			Max_iter:    100,
+			Inertia:     Constriction,
+			Phi_p:       2.05,
+			Phi_g:       2.05,
+			Opposition:  opposition}
+		min := Optimize(f, b_low, b_up, params)
+		t.Log(opposition, f(min))
+	}
+}
+
+func TestOpposeParticles(t *testing.T) {
+	f := func(x mat.Vector) float64 {
+		return math.Pow(x.AtVec(0)-1.0, 2.0)
+	}
+	// the opposites within [-4, 4] are 4, 3, -2, -3
+	x := []*mat.VecDense{
+		mat.NewVecDense(1, []float64{-4.0}),
+		mat.NewVecDense(1, []float64{-3.0}),
+		mat.NewVecDense(1, []float64{2.0}),
+		mat.NewVecDense(1, []float64{3.0})}
+	p := make([]*mat.VecDense, len(x))
+	value_x := make([]float64, len(x))
+	for i, e := range x {
+		p[i] = mat.VecDenseCopyOf(e)
+		value_x[i] = f(e)
+	}
+	value_p := append([]float64(nil), value_x...)
+	value_p[3] = 0.0
+	b_low := mat.NewVecDense(1, []float64{-4.0})
+	b_up := mat.NewVecDense(1, []float64{4.0})
+	opposeParticles(f, x, value_x, p, value_p, b_low, b_up)
+	t.Log(value_x, value_p)
+	// the best half of {-4, -3, 2, 3, 4, 3, -2, -3} is {2, 3, 3, 4}, particle 3 keeps its better personal best
+	if x[0].AtVec(0) != 4.0 || x[1].AtVec(0) != 3.0 || x[2].AtVec(0) != 2.0 || x[3].AtVec(0) != 3.0 ||
+		p[0].AtVec(0) != 4.0 || value_p[3] != 0.0 {
+		t.Fatal("TestOpposeParticles fails")
+	}
+}
+
+func TestPsoInit(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	for _, init := range []sampling.Sampler{sampling.LatinHypercube, sampling.Sobol, sampling.Halton} {
+		params := &Params{
+			N_particles: 10,
+			Max_iter:    50,
+			Inertia:     Constriction,
+			Phi_p:       2.05,
+			Phi_g:       2.05,
+			Init:        init}
+		min := Optimize(f, b_low, b_up, params)
+		t.Log(min, f(min))
+	}
+}
+
+func TestFindOptima(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-5.0, -5.0})
+	b_up := mat.NewVecDense(2, []float64{5.0, 5.0})
+	himmelblau := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow(x0*x0+x1-11.0, 2.0) + math.Pow(x0+x1*x1-7.0, 2.0)
+	}
+	params := &Params{
+		N_particles:  200,
+		Max_iter:     200,
+		Inertia:      Constriction,
+		Phi_p:        2.05,
+		Phi_g:        2.05,
+		Niche_radius: 1.0,
+		Species_size: 20}
+	found := 0
+	for _, o := range FindOptima(himmelblau, b_low, b_up, params) {
+		if o.Value < 1e-4 {
+			t.Log(o.X, o.Value)
+			found++
+		}
+	}
+	if found != 4 {
+		t.Fatal("TestFindOptima expects the 4 minima of Himmelblau's function, found", found)
+	}
+}
+
+func TestOptimizeBinary(t *testing.T) {
+	// select the features with positive weight
+	weights := []float64{3.0, -1.0, 2.0, -4.0, 0.5, -0.5, 1.0, -2.0, 5.0, -3.0}
+	f := func(x []bool) float64 {
+		res := 0.0
+		for i, selected := range x {
+			if selected {
+				res = res - weights[i]
+			}
+		}
+		return res
+	}
+	for _, transfer := range []Transfer{SShaped, VShaped} {
+		params := &Params{
+			N_particles: 30,
+			Max_iter:    100,
+			Omega:       0.7,
+			Phi_p:       1.5,
+			Phi_g:       1.5,
+			Transfer:    transfer}
+		best := OptimizeBinary(f, len(weights), params)
+		t.Log(transfer, best, f(best))
+	}
+}
+
+func TestOptimizeInteger(t *testing.T) {
+	f := func(x []int) float64 {
+		return math.Pow(float64(x[0]-3), 2.0) + math.Pow(float64(x[1]+7), 2.0)
+	}
+	params := &Params{N_particles: 30, Max_iter: 100, Omega: 0.7, Phi_p: 1.5, Phi_g: 1.5}
+	best := OptimizeInteger(f, []int{-10, -10}, []int{10, 10}, params)
+	t.Log(best)
+	if best[0] != 3 || best[1] != -7 {
+		t.Fatal("TestOptimizeInteger fails")
+	}
+}
+
+func TestOptimizeAsync(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	params := &Params{
+		N_particles: 50,
+		Max_iter:    100,
+		Omega:       0.7,
+		Phi_p:       1.5,
+		Phi_g:       1.5,
+		Topology:    Ring}
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	min := OptimizeAsync(f, b_low, b_up, params)
+	t.Log(min)
+	t.Log(f(min))
+}
+
+// an objective whose evaluation takes between 0 and 2ms
+func slowTarget(x mat.Vector) float64 {
+	time.Sleep(time.Duration(rand.Intn(2000)) * time.Microsecond)
+	return math.Pow(x.AtVec(0), 2.0) + math.Pow(x.AtVec(1), 2.0)
+}
+
+var slowParams = &Params{N_particles: 20, Max_iter: 10, Omega: 0.7, Phi_p: 1.5, Phi_g: 1.5}
+
+// synchronous PSO evaluating the particles of an iteration in parallel, with a barrier between iterations
+func optimizeParallel(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
+	r := common.GetNewRand()
+	p := initParticles(b_low, b_up, params.N_particles, params.Init)
+	v := initVelocity(b_low, b_up, params.N_particles)
+	x := initParticlePositions(p)
+	value_p := evaluateParticles(f, p)
+	best := common.SelectBest(value_p, 1)[0]
+	g := mat.VecDenseCopyOf(p[best])
+	value_g := value_p[best]
+	value_x := make([]float64, params.N_particles)
+	for iter := 0; iter < params.Max_iter; iter++ {
+		t := float64(iter) / math.Max(float64(params.Max_iter-1), 1.0)
+		var wg sync.WaitGroup
+		for i := 0; i < params.N_particles; i++ {
+			updateVelocity(v[i], x[i], p[i], g, computeCoefficients(t, 0.5, params, r), params.Scalar_random)
+			clampVelocity(v[i], b_low, b_up, params.V_max)
+			updateParticlePositions(x[i], v[i], learningRate(params), b_low, b_up, params.Boundary)
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				value_x[i] = f(x[i])
+			}(i)
+		}
+		wg.Wait()
+		for i := 0; i < params.N_particles; i++ {
+			if value_x[i] < value_p[i] {
+				p[i].CopyVec(x[i])
+				value_p[i] = value_x[i]
+			}
+			if value_x[i] < value_g {
+				g.CopyVec(x[i])
+				value_g = value_x[i]
+			}
+		}
+	}
+	return g
+}
+
+func BenchmarkOptimizeSlow(b *testing.B) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	for i := 0; i < b.N; i++ {
+		Optimize(slowTarget, b_low, b_up, slowParams)
+	}
+}
+
+func BenchmarkOptimizeParallelSlow(b *testing.B) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	for i := 0; i < b.N; i++ {
+		optimizeParallel(slowTarget, b_low, b_up, slowParams)
+	}
+}
+
+func BenchmarkOptimizeAsyncSlow(b *testing.B) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	for i := 0; i < b.N; i++ {
+		OptimizeAsync(slowTarget, b_low, b_up, slowParams)
+	}
+}
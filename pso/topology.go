@@ -0,0 +1,114 @@
+package pso
+
+import (
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Topology int
+
+const (
+	Global     Topology = iota // every particle follows the global best
+	Ring                       // Neighbours particles on each side, defaults to 1
+	VonNeumann                 // up, down, left and right on a toroidal grid
+	Random                     // each particle informs Neighbours random others, defaults to 3
+	Dynamic                    // ring whose radius grows until it covers the whole swarm at Max_iter
+)
+
+// updateNeighbours returns the neighbourhoods (including the particle itself) used in iteration iter.
+// Random topologies are re-wired every Rewire_every iterations or, if not set, after an iteration without
+// improvement of the global best.
+func updateNeighbours(neighbours [][]int, iter int, improved bool, params *Params, r *rand.Rand) [][]int {
+	n := params.N_particles
+	switch params.Topology {
+	case Ring:
+		if neighbours == nil {
+			k := params.Neighbours
+			if k <= 0 {
+				k = 1
+			}
+			return ringNeighbours(n, k)
+		}
+	case VonNeumann:
+		if neighbours == nil {
+			return vonNeumannNeighbours(n)
+		}
+	case Random:
+		rewire := !improved
+		if params.Rewire_every > 0 {
+			rewire = iter%params.Rewire_every == 0
+		}
+		if neighbours == nil || rewire {
+			k := params.Neighbours
+			if k <= 0 {
+				k = 3
+			}
+			return randomNeighbours(n, k, r)
+		}
+	case Dynamic:
+		progress := float64(iter) / math.Max(float64(params.Max_iter-1), 1.0)
+		return ringNeighbours(n, 1+int(progress*float64(n/2-1)))
+	}
+	return neighbours
+}
+
+func ringNeighbours(n int, k int) [][]int {
+	res := make([][]int, n)
+	for i := 0; i < n; i++ {
+		res[i] = []int{i}
+		for d := 1; d <= k && 2*d <= n; d++ {
+			res[i] = append(res[i], (i+d)%n, (i-d+n)%n)
+		}
+	}
+	return res
+}
+
+func vonNeumannNeighbours(n int) [][]int {
+	rows := int(math.Max(math.Floor(math.Sqrt(float64(n))), 1.0))
+	cols := int(math.Ceil(float64(n) / float64(rows)))
+	res := make([][]int, n)
+	for i := 0; i < n; i++ {
+		row, col := i/cols, i%cols
+		res[i] = []int{i}
+		candidates := []int{
+			((row+rows-1)%rows)*cols + col,
+			((row+1)%rows)*cols + col,
+			row*cols + (col+cols-1)%cols,
+			row*cols + (col+1)%cols}
+		for _, j := range candidates {
+			if j < n && j != i {
+				res[i] = append(res[i], j)
+			}
+		}
+	}
+	return res
+}
+
+// every particle informs itself and k randomly chosen particles
+func randomNeighbours(n int, k int, r *rand.Rand) [][]int {
+	res := make([][]int, n)
+	for i := 0; i < n; i++ {
+		res[i] = append(res[i], i)
+	}
+	for i := 0; i < n; i++ {
+		for d := 0; d < k; d++ {
+			j := r.Intn(n)
+			if j != i {
+				res[j] = append(res[j], i)
+			}
+		}
+	}
+	return res
+}
+
+func localBest(neighbours []int, p []*mat.VecDense, value_p []float64) *mat.VecDense {
+	best := neighbours[0]
+	for _, j := range neighbours {
+		if value_p[j] < value_p[best] {
+			best = j
+		}
+	}
+	return p[best]
+}
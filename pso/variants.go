@@ -0,0 +1,69 @@
+package pso
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Variant int
+
+const (
+	Standard  Variant = iota
+	BareBones         // samples x from N((p + l) / 2, |p - l|) per dimension, no parameters (Kennedy 2003)
+	Quantum           // QPSO around a random attractor between p and l, uses Beta (Sun et al. 2004)
+	// CLPSO, every dimension learns from another particle's personal best, Phi_p is the acceleration
+	// (defaults to 1.49445) and Refresh_gap the patience until new exemplars are chosen (defaults to 7)
+	ComprehensiveLearning
+)
+
+// moveBareBones samples a new position between the personal best p and the neighbourhood best l
+func moveBareBones(x *mat.VecDense, p *mat.VecDense, l *mat.VecDense, r *rand.Rand) {
+	for i := 0; i < x.Len(); i++ {
+		mean := (p.AtVec(i) + l.AtVec(i)) / 2.0
+		std := math.Abs(p.AtVec(i) - l.AtVec(i))
+		x.SetVec(i, mean+std*r.NormFloat64())
+	}
+}
+
+// moveQuantum places x around a local attractor with a spread given by the distance to the mean best position
+func moveQuantum(x *mat.VecDense, p *mat.VecDense, l *mat.VecDense, mbest *mat.VecDense,
+	beta float64, r *rand.Rand) {
+	for i := 0; i < x.Len(); i++ {
+		phi := r.Float64()
+		attractor := phi*p.AtVec(i) + (1.0-phi)*l.AtVec(i)
+		u := 1.0 - r.Float64()
+		spread := beta * math.Abs(mbest.AtVec(i)-x.AtVec(i)) * math.Log(1.0/u)
+		if r.Float64() < 0.5 {
+			x.SetVec(i, attractor+spread)
+		} else {
+			x.SetVec(i, attractor-spread)
+		}
+	}
+}
+
+// contraction-expansion coefficient of QPSO, if Beta is not set it decreases linearly from 1.0 to 0.5
+func contractionExpansion(t float64, params *Params) float64 {
+	if params.Beta > 0.0 {
+		return params.Beta
+	}
+	return 1.0 - 0.5*t
+}
+
+func meanBest(p []*mat.VecDense) *mat.VecDense {
+	res := mat.NewVecDense(p[0].Len(), nil)
+	for _, e := range p {
+		res.AddVec(res, e)
+	}
+	res.ScaleVec(1.0/float64(len(p)), res)
+	return res
+}
+
+func clampPosition(x *mat.VecDense, b_low mat.Vector, b_up mat.Vector) {
+	common.ApplyElementwise(x, func(e float64, idx int) float64 {
+		return math.Min(math.Max(b_low.AtVec(idx), e), b_up.AtVec(idx))
+	})
+}
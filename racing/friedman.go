@@ -0,0 +1,85 @@
+package racing
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// friedman tests whether the candidates (columns) differ on the instances (rows), lower results being better.
+// It returns the rank sums, the p-value and the indices of the candidates which are worse than the best one
+// according to the post-hoc comparison of Conover, as used by F-Race (Birattari et al. 2002).
+func friedman(results [][]float64, alpha float64) ([]float64, float64, []int) {
+	b := float64(len(results))
+	k := len(results[0])
+	ranks := make([][]float64, len(results))
+	rank_sums := make([]float64, k)
+	a_1 := 0.0
+	for i, row := range results {
+		ranks[i] = rank(row)
+		for j, r := range ranks[i] {
+			rank_sums[j] = rank_sums[j] + r
+			a_1 = a_1 + r*r
+		}
+	}
+	if k < 2 || b < 2 {
+		return rank_sums, 1.0, nil
+	}
+	kf := float64(k)
+	c_1 := b * kf * math.Pow(kf+1.0, 2.0) / 4.0
+	if a_1 == c_1 {
+		// all instances rank all candidates equal
+		return rank_sums, 1.0, nil
+	}
+	t_1 := 0.0
+	sum_r2 := 0.0
+	for _, r := range rank_sums {
+		t_1 = t_1 + math.Pow(r-b*(kf+1.0)/2.0, 2.0)
+		sum_r2 = sum_r2 + r*r
+	}
+	t_1 = (kf - 1.0) * t_1 / (a_1 - c_1)
+	p := distuv.ChiSquared{K: kf - 1.0}.Survival(t_1)
+	if p >= alpha {
+		return rank_sums, p, nil
+	}
+	df := (b - 1.0) * (kf - 1.0)
+	t := distuv.StudentsT{Mu: 0.0, Sigma: 1.0, Nu: df}.Quantile(1.0 - alpha/2.0)
+	critical := t * math.Sqrt(math.Max(2.0*(b*a_1-sum_r2)/df, 0.0))
+	best := 0
+	for j, r := range rank_sums {
+		if r < rank_sums[best] {
+			best = j
+		}
+	}
+	worse := make([]int, 0)
+	for j, r := range rank_sums {
+		if r-rank_sums[best] > critical {
+			worse = append(worse, j)
+		}
+	}
+	return rank_sums, p, worse
+}
+
+// ranks starting with 1 for the smallest value, ties get their average rank
+func rank(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return values[order[a]] < values[order[b]]
+	})
+	res := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+		for l := i; l <= j; l++ {
+			res[order[l]] = float64(i+j)/2.0 + 1.0
+		}
+		i = j + 1
+	}
+	return res
+}
@@ -0,0 +1,211 @@
+package racing
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/applied-math-coding/heuristic/common"
+	"github.com/applied-math-coding/heuristic/meta_opt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Params = struct {
+	N_iterations    int     // races, defaults to 3
+	N_candidates    int     // configurations per race including the elites, defaults to 20
+	N_elites        int     // configurations surviving a race, defaults to 3
+	First_test      int     // instances before the first test, defaults to 5
+	Max_instances   int     // instances per race, defaults to 30
+	Alpha           float64 // significance level of the Friedman test, defaults to 0.05
+	Max_experiments int     // runs of the algorithm over all races, 0 means unlimited
+}
+
+// Configuration is a candidate of the race. Results holds the best value found per instance of the current race.
+type Configuration = struct {
+	Values    map[string]float64
+	Results   []float64
+	Mean_rank float64 // within the last race, lower is better
+	Iteration int     // race in which the configuration was sampled
+	unit      []float64
+}
+
+type Result = struct {
+	Elites      []*Configuration // best first
+	Experiments int
+	Log         []string // course of the races, see WriteReport
+}
+
+// Configure runs an iterated race (Lopez-Ibanez et al. 2016) over the parameters of algorithm.
+// An instance is one run on a problem, the problems being used in turn. As the optimizers of this module
+// seed themselves, repeated instances of a problem are independent runs.
+// The first race samples uniformly, later ones sample around the elites of the previous race with
+// a shrinking normal distribution. Within a race, the candidates are evaluated instance by instance and
+// after First_test instances the ones worse than the best according to a Friedman test are eliminated.
+func Configure(algorithm *meta_opt.Algorithm, problems []*meta_opt.Problem, params *Params) *Result {
+	r := common.GetNewRand()
+	n_iterations := defaultInt(params.N_iterations, 3)
+	n_candidates := defaultInt(params.N_candidates, 20)
+	n_elites := defaultInt(params.N_elites, 3)
+	res := &Result{}
+	elites := make([]*Configuration, 0)
+	instance := 0
+	for iter := 1; iter <= n_iterations; iter++ {
+		candidates := append([]*Configuration(nil), elites...)
+		for len(candidates) < n_candidates {
+			candidates = append(candidates, sampleConfiguration(algorithm.Parameters, elites, iter, r))
+		}
+		if params.Max_experiments > 0 && res.Experiments+len(candidates) > params.Max_experiments {
+			res.Log = append(res.Log, "budget of experiments exhausted")
+			break
+		}
+		res.Log = append(res.Log, fmt.Sprintf("race %d: %d candidates, %d elites", iter, len(candidates), len(elites)))
+		var exhausted bool
+		elites, instance, exhausted = race(algorithm, problems, candidates, instance, n_elites, params, res)
+		if exhausted {
+			res.Log = append(res.Log, "budget of experiments exhausted")
+			break
+		}
+	}
+	res.Elites = elites
+	return res
+}
+
+// race returns the n_elites best surviving candidates, the next instance and whether the budget is exhausted
+func race(algorithm *meta_opt.Algorithm, problems []*meta_opt.Problem, candidates []*Configuration, instance int,
+	n_elites int, params *Params, res *Result) ([]*Configuration, int, bool) {
+	first_test := defaultInt(params.First_test, 5)
+	max_instances := defaultInt(params.Max_instances, 30)
+	alpha := params.Alpha
+	if alpha <= 0.0 {
+		alpha = 0.05
+	}
+	for _, c := range candidates {
+		c.Results = nil
+	}
+	alive := candidates
+	exhausted := false
+	for step := 1; step <= max_instances && len(alive) > n_elites; step++ {
+		if params.Max_experiments > 0 && res.Experiments+len(alive) > params.Max_experiments {
+			exhausted = true
+			break
+		}
+		p := problems[instance%len(problems)]
+		instance++
+		for _, c := range alive {
+			c.Results = append(c.Results, p.F(algorithm.Run(c.Values, p)))
+			res.Experiments++
+		}
+		if step < first_test {
+			continue
+		}
+		rank_sums, p_value, worse := friedman(resultTable(alive), alpha)
+		updateMeanRanks(alive, rank_sums)
+		if len(worse) == 0 {
+			continue
+		}
+		eliminated := make(map[int]bool)
+		for _, j := range worse {
+			eliminated[j] = true
+		}
+		// eliminated candidates rank behind the others, the best of them are kept if too few survive
+		survivors := make([]*Configuration, 0, len(alive))
+		for _, j := range byRank(alive) {
+			if !eliminated[j] || len(survivors) < n_elites {
+				survivors = append(survivors, alive[j])
+			}
+		}
+		res.Log = append(res.Log, fmt.Sprintf("  instance %d: p = %.4f, %d of %d candidates eliminated",
+			step, p_value, len(alive)-len(survivors), len(alive)))
+		alive = survivors
+	}
+	if len(alive) > 0 && len(alive[0].Results) > 0 {
+		rank_sums, _, _ := friedman(resultTable(alive), alpha)
+		updateMeanRanks(alive, rank_sums)
+	}
+	elites := make([]*Configuration, 0, n_elites)
+	for _, j := range byRank(alive) {
+		if len(elites) < n_elites {
+			elites = append(elites, alive[j])
+		}
+	}
+	for _, e := range elites {
+		res.Log = append(res.Log, fmt.Sprintf("  elite %v, mean rank %.2f over %d instances",
+			e.Values, e.Mean_rank, len(e.Results)))
+	}
+	return elites, instance, exhausted
+}
+
+// rows are instances, columns candidates
+func resultTable(candidates []*Configuration) [][]float64 {
+	n := len(candidates[0].Results)
+	res := make([][]float64, n)
+	for i := range res {
+		res[i] = make([]float64, len(candidates))
+		for j, c := range candidates {
+			res[i][j] = c.Results[i]
+		}
+	}
+	return res
+}
+
+func updateMeanRanks(candidates []*Configuration, rank_sums []float64) {
+	for j, c := range candidates {
+		c.Mean_rank = rank_sums[j] / float64(len(c.Results))
+	}
+}
+
+func byRank(candidates []*Configuration) []int {
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return candidates[order[a]].Mean_rank < candidates[order[b]].Mean_rank
+	})
+	return order
+}
+
+// without elites uniform, otherwise normal around an elite chosen by rank,
+// the standard deviation in the unit cube halves with every race
+func sampleConfiguration(parameters []meta_opt.Parameter, elites []*Configuration, iter int,
+	r *rand.Rand) *Configuration {
+	u := make([]float64, len(parameters))
+	if len(elites) == 0 {
+		for i := range u {
+			u[i] = r.Float64()
+		}
+	} else {
+		weights := make([]float64, len(elites))
+		sum := 0.0
+		for i := range elites {
+			weights[i] = float64(len(elites) - i)
+			sum = sum + weights[i]
+		}
+		parent := elites[len(elites)-1]
+		x := r.Float64() * sum
+		for i, w := range weights {
+			if x < w {
+				parent = elites[i]
+				break
+			}
+			x = x - w
+		}
+		sd := math.Pow(0.5, float64(iter-1)) / 2.0
+		for i := range u {
+			u[i] = math.Min(math.Max(parent.unit[i]+sd*r.NormFloat64(), 0.0), 1.0)
+		}
+	}
+	return &Configuration{
+		Values:    meta_opt.ToValues(parameters, mat.NewVecDense(len(u), u)),
+		Iteration: iter,
+		unit:      u}
+}
+
+func defaultInt(v int, d int) int {
+	if v > 0 {
+		return v
+	}
+	return d
+}
@@ -0,0 +1,71 @@
+package racing
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/applied-math-coding/heuristic/de"
+	"github.com/applied-math-coding/heuristic/meta_opt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRank(t *testing.T) {
+	ranks := rank([]float64{3.0, 1.0, 3.0, 2.0})
+	if ranks[0] != 3.5 || ranks[1] != 1.0 || ranks[2] != 3.5 || ranks[3] != 2.0 {
+		t.Fatal(ranks)
+	}
+}
+
+func TestFriedman(t *testing.T) {
+	// the third candidate is always worst, the first two alternate
+	results := make([][]float64, 10)
+	for i := range results {
+		results[i] = []float64{float64(i % 2), float64((i + 1) % 2), 5.0}
+	}
+	rank_sums, p, worse := friedman(results, 0.05)
+	t.Log(rank_sums, p, worse)
+	if len(worse) != 1 || worse[0] != 2 {
+		t.Fatal("expected the third candidate to be eliminated", worse)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	rastrigin := func(x mat.Vector) float64 {
+		res := 10.0 * float64(x.Len())
+		for i := 0; i < x.Len(); i++ {
+			res = res + math.Pow(x.AtVec(i), 2.0) - 10.0*math.Cos(2.0*math.Pi*x.AtVec(i))
+		}
+		return res
+	}
+	b_low := mat.NewVecDense(5, []float64{-5.0, -5.0, -5.0, -5.0, -5.0})
+	b_up := mat.NewVecDense(5, []float64{5.0, 5.0, 5.0, 5.0, 5.0})
+	problems := []*meta_opt.Problem{{F: rastrigin, B_low: b_low, B_up: b_up}}
+	params := &Params{
+		N_iterations:    3,
+		N_candidates:    10,
+		Max_experiments: 500}
+	res := Configure(meta_opt.DE(de.Params{N_agents: 20, Max_iter: 30}), problems, params)
+	if len(res.Elites) == 0 || res.Experiments > params.Max_experiments {
+		t.Fatal(len(res.Elites), res.Experiments)
+	}
+	var report bytes.Buffer
+	if err := WriteReport(&report, res); err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report.String())
+	dir, err := ioutil.TempDir("", "racing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := WriteFiles(dir, res); err != nil {
+		t.Fatal(err)
+	}
+	configs, _ := ioutil.ReadFile(filepath.Join(dir, "configs.json"))
+	t.Log(string(configs))
+}
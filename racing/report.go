@@ -0,0 +1,79 @@
+package racing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WriteConfigs writes the parameter values of the elites as a JSON array, best first.
+func WriteConfigs(w io.Writer, res *Result) error {
+	configs := make([]map[string]float64, len(res.Elites))
+	for i, e := range res.Elites {
+		configs[i] = e.Values
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(configs)
+}
+
+// WriteReport writes the course of the races and a summary of the elites as text.
+func WriteReport(w io.Writer, res *Result) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "experiments: %d\n\n", res.Experiments)
+	for _, line := range res.Log {
+		fmt.Fprintln(&b, line)
+	}
+	fmt.Fprintln(&b, "\nelites:")
+	for i, e := range res.Elites {
+		names := make([]string, 0, len(e.Values))
+		for name := range e.Values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		values := make([]string, len(names))
+		for k, name := range names {
+			values[k] = fmt.Sprintf("%s=%g", name, e.Values[name])
+		}
+		fmt.Fprintf(&b, "%d. %s (race %d, mean rank %.2f, mean result %g)\n",
+			i+1, strings.Join(values, " "), e.Iteration, e.Mean_rank, mean(e.Results))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteFiles writes configs.json and report.txt into dir.
+func WriteFiles(dir string, res *Result) error {
+	if err := writeFile(filepath.Join(dir, "configs.json"), res, WriteConfigs); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, "report.txt"), res, WriteReport)
+}
+
+// the error of Close is returned as well, since a failed write may only be reported there
+func writeFile(name string, res *Result, write func(w io.Writer, res *Result) error) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(file, res); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func mean(x []float64) float64 {
+	if len(x) == 0 {
+		return 0.0
+	}
+	sum := 0.0
+	for _, e := range x {
+		sum = sum + e
+	}
+	return sum / float64(len(x))
+}
@@ -16,6 +16,7 @@ type Params = struct {
 	Location_Precision float64 // min distance to distinguish several roots
 	N_particles        int     // based on the dimension and size of interval, one must play with this
 	Precision          float64 // aimed precission of root
+	Niching            bool    // locate all roots in one species-based PSO run instead of the bisection
 }
 
 type Segment = struct {
@@ -26,14 +27,20 @@ type Segment = struct {
 // FindRoots tries to find all roots of f in given multi-dim-interval. The algorithm uses a heuristic search (PSO)
 // which imposes no restrictions on f. A recursive bisection procedure ensures the pso-search to provide
 // as much as possible independent results.
+// With params.Niching the bisection is replaced by a single niching PSO run which keeps one species per basin.
 // In case the system is n*n and a derivative is supplied, it is used for a Newton-method to refine roots.
 // If the system is n*n and no derivative is supplied, the derivative will be approximated internally.
 func FindRoots(f common.System, D common.Derivative, b_low mat.Vector, b_up mat.Vector,
 	params *Params) []mat.Vector {
-	pso_params := meta_opt_pso.Optimize(createTargetFn(f), b_low, b_up)
-	pso_params.Max_iter = 100
-	pso_params.N_particles = int(math.Max(500.0, float64(params.N_particles)))
-	roots := recFindRoots(f, b_low, b_up, params, pso_params, nil)
+	var roots []mat.Vector
+	if params.Niching {
+		roots = findRootsByNiching(f, b_low, b_up, params)
+	} else {
+		pso_params := meta_opt_pso.Optimize(createTargetFn(f), b_low, b_up)
+		pso_params.Max_iter = 100
+		pso_params.N_particles = int(math.Max(500.0, float64(params.N_particles)))
+		roots = recFindRoots(f, b_low, b_up, params, pso_params, nil)
+	}
 	res := make([]mat.Vector, 0)
 	for _, r := range roots {
 		if !isRootContainedInList(params.Location_Precision, r, res) {
@@ -152,17 +159,40 @@ func createTargetFn(f common.System) common.Target {
 
 func searchRoot(f common.System, b_low mat.Vector, b_up mat.Vector, pso_params *pso.Params, params *Params) mat.Vector {
 	x_0 := pso.Optimize(createTargetFn(f), b_low, b_up, pso_params)
-	isRoot := true
-	y_0 := f(x_0)
-	for i := 0; i < x_0.Len(); i++ {
-		isRoot = math.Abs(y_0.AtVec(i)) <= params.Root_Recognition
-		if !isRoot {
-			return nil
-		}
+	if !isRoot(f, x_0, params) {
+		return nil
 	}
 	return x_0
 }
 
+func findRootsByNiching(f common.System, b_low mat.Vector, b_up mat.Vector, params *Params) []mat.Vector {
+	pso_params := &pso.Params{
+		N_particles:  int(math.Max(500.0, float64(params.N_particles))),
+		Max_iter:     200,
+		Inertia:      pso.Constriction,
+		Phi_p:        2.05,
+		Phi_g:        2.05,
+		Niche_radius: params.Location_Precision,
+		Species_size: 20}
+	res := make([]mat.Vector, 0)
+	for _, o := range pso.FindOptima(createTargetFn(f), b_low, b_up, pso_params) {
+		if isRoot(f, o.X, params) {
+			res = append(res, o.X)
+		}
+	}
+	return res
+}
+
+func isRoot(f common.System, x mat.Vector, params *Params) bool {
+	y := f(x)
+	for i := 0; i < y.Len(); i++ {
+		if math.Abs(y.AtVec(i)) > params.Root_Recognition {
+			return false
+		}
+	}
+	return true
+}
+
 func splitInterval(idx int, b_low mat.Vector, b_up mat.Vector) (mat.Vector, mat.Vector) {
 	n := b_low.Len()
 	mid := b_low.AtVec(idx) + 0.5*(b_up.AtVec(idx)-b_low.AtVec(idx))
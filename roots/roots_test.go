@@ -22,3 +22,23 @@ func TestFindRoots(t *testing.T) {
 		t.Log(r)
 	}
 }
+
+func TestFindRootsNiching(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	f := func(x mat.Vector) mat.Vector {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return mat.NewVecDense(2, []float64{
+			(1.0 - x1) * x0,
+			x1 * (2.0 - x0)})
+	}
+	params := &Params{Location_Precision: 0.5, Root_Recognition: 0.1, Precision: 0.0000001, Niching: true}
+	roots := FindRoots(f, nil, b_low, b_up, params)
+	for _, r := range roots {
+		t.Log(r)
+	}
+	if len(roots) != 2 {
+		t.Fatal("TestFindRootsNiching expects 2 roots, found", len(roots))
+	}
+}
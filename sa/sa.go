@@ -0,0 +1,196 @@
+package sa
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type Cooling int
+
+const (
+	Exponential Cooling = iota // T_k = T_0 * Alpha^k
+	Logarithmic                // T_k = T_0 / ln(e + k)
+	Lam                        // adapts T to follow the acceptance ratio proposed by Lam and Delosme
+	Reheating                  // exponential, but T is raised again after Reheat_after steps without improvement
+)
+
+type Neighbourhood int
+
+const (
+	Gaussian Neighbourhood = iota
+	Cauchy
+)
+
+type Params = struct {
+	Max_iter      int
+	T_0           float64
+	Cooling       Cooling
+	Alpha         float64 // cooling factor of Exponential and Reheating, defaults to 0.95
+	Reheat_after  int     // iterations without improvement until Reheating raises T
+	Reheat_factor float64 // Reheating sets T to Reheat_factor * T_0, defaults to 1
+	Neighbourhood Neighbourhood
+	Step          float64 // scale of continuous moves relative to b_up - b_low, defaults to 0.1
+	Move          Move    // optional, replaces Neighbourhood in Optimize
+}
+
+// State is any candidate solution, e.g. a mat.Vector or a permutation of jobs.
+type State = interface{}
+type Energy = func(s State) float64
+
+// Move returns a new neighbour of s. It must not modify s.
+type Move = func(s State, r *rand.Rand) State
+
+type temperature = struct {
+	T                 float64
+	acceptance_rate   float64
+	since_improvement int
+}
+
+// Optimize searches a minimum of f within the given bounds. Unless params.Move is given,
+// neighbours are drawn from a Gaussian or Cauchy distribution scaled to b_up - b_low.
+func Optimize(f common.Target, b_low mat.Vector, b_up mat.Vector, params *Params) mat.Vector {
+	move := params.Move
+	if move == nil {
+		move = createContinuousMove(b_low, b_up, params)
+	}
+	s := Anneal(
+		func(s State) float64 { return f(s.(mat.Vector)) },
+		common.RandomDataInBounds(b_low, b_up),
+		move,
+		params)
+	return s.(mat.Vector)
+}
+
+// Anneal minimizes e starting from s_0 by moving through the neighbourhood given by move.
+// Worse states are accepted by the Metropolis criterion. The best state seen is returned.
+func Anneal(e Energy, s_0 State, move Move, params *Params) State {
+	r := common.GetNewRand()
+	s := s_0
+	value := e(s)
+	best := s
+	best_value := value
+	temp := &temperature{T: params.T_0, acceptance_rate: 0.5}
+	for iter := 0; iter < params.Max_iter; iter++ {
+		y := move(s, r)
+		y_value := e(y)
+		accepted := isAccepted(value, y_value, temp.T, r)
+		if accepted {
+			s = y
+			value = y_value
+		}
+		if value < best_value {
+			best = s
+			best_value = value
+			temp.since_improvement = 0
+		} else {
+			temp.since_improvement++
+		}
+		cool(temp, iter, accepted, params)
+	}
+	return best
+}
+
+// Metropolis acceptance
+func isAccepted(value float64, y_value float64, T float64, r *rand.Rand) bool {
+	if y_value <= value {
+		return true
+	}
+	if T <= 0.0 {
+		return false
+	}
+	return r.Float64() < math.Exp(-(y_value-value)/T)
+}
+
+func cool(temp *temperature, iter int, accepted bool, params *Params) {
+	switch params.Cooling {
+	case Exponential:
+		temp.T = temp.T * coolingFactor(params)
+	case Logarithmic:
+		temp.T = params.T_0 / math.Log(math.E+float64(iter+1))
+	case Lam:
+		a := 0.0
+		if accepted {
+			a = 1.0
+		}
+		temp.acceptance_rate = (499.0*temp.acceptance_rate + a) / 500.0
+		if temp.acceptance_rate > lamTargetRate(float64(iter+1)/float64(params.Max_iter)) {
+			temp.T = 0.999 * temp.T
+		} else {
+			temp.T = temp.T / 0.999
+		}
+	case Reheating:
+		temp.T = temp.T * coolingFactor(params)
+		if params.Reheat_after > 0 && temp.since_improvement >= params.Reheat_after {
+			reheat_factor := params.Reheat_factor
+			if reheat_factor <= 0.0 {
+				reheat_factor = 1.0
+			}
+			temp.T = reheat_factor * params.T_0
+			temp.since_improvement = 0
+		}
+	}
+}
+
+func coolingFactor(params *Params) float64 {
+	if params.Alpha > 0.0 {
+		return params.Alpha
+	}
+	return 0.95
+}
+
+// aimed acceptance ratio at progress t in [0, 1] (Lam, Delosme 1988 as given by Swartz)
+func lamTargetRate(t float64) float64 {
+	switch {
+	case t < 0.15:
+		return 0.44 + 0.56*math.Pow(560.0, -t/0.15)
+	case t < 0.65:
+		return 0.44
+	default:
+		return 0.44 * math.Pow(440.0, -(t-0.65)/0.35)
+	}
+}
+
+func createContinuousMove(b_low mat.Vector, b_up mat.Vector, params *Params) Move {
+	return func(s State, r *rand.Rand) State {
+		x := s.(mat.Vector)
+		y := mat.NewVecDense(x.Len(), nil)
+		for i := 0; i < x.Len(); i++ {
+			var z float64
+			if params.Neighbourhood == Cauchy {
+				z = math.Tan(math.Pi * (r.Float64() - 0.5))
+			} else {
+				z = r.NormFloat64()
+			}
+			width := b_up.AtVec(i) - b_low.AtVec(i)
+			y.SetVec(i, reflect(x.AtVec(i)+step(params)*width*z, b_low.AtVec(i), b_up.AtVec(i)))
+		}
+		return y
+	}
+}
+
+func step(params *Params) float64 {
+	if params.Step > 0.0 {
+		return params.Step
+	}
+	return 0.1
+}
+
+// mirrors e at the bounds, which keeps moves near a bound from piling up on it
+func reflect(e float64, low float64, up float64) float64 {
+	width := up - low
+	if width <= 0.0 {
+		return low
+	}
+	e = math.Mod(e-low, 2.0*width)
+	if e < 0.0 {
+		e = e + 2.0*width
+	}
+	if e > width {
+		e = 2.0*width - e
+	}
+	return low + e
+}
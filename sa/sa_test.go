@@ -0,0 +1,101 @@
+package sa
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSa(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	f := func(x mat.Vector) float64 {
+		x0 := x.AtVec(0)
+		x1 := x.AtVec(1)
+		return math.Pow((1.0-x1)*x0, 2.0) + math.Pow(x1*(2.0-x0), 2.0)
+	}
+	for _, cooling := range []Cooling{Exponential, Logarithmic, Lam, Reheating} {
+		params := &Params{
+			Max_iter:      10000,
+			T_0:           10.0,
+			Cooling:       cooling,
+			Alpha:         0.999,
+			Reheat_after:  1000,
+			Reheat_factor: 0.1,
+			Neighbourhood: Cauchy,
+			Step:          0.01}
+		min := Optimize(f, b_low, b_up, params)
+		t.Log(min)
+		t.Log(f(min))
+		if f(min) > 1e-2 {
+			t.Fatal("TestSa fails for cooling", cooling)
+		}
+	}
+}
+
+func TestCoolingFactor(t *testing.T) {
+	temp := &temperature{T: 10.0}
+	cool(temp, 0, false, &Params{Cooling: Exponential})
+	if temp.T != 9.5 {
+		t.Fatal("TestCoolingFactor fails", temp.T)
+	}
+}
+
+func TestAnnealScheduling(t *testing.T) {
+	duration := []float64{3.0, 2.0, 4.0, 1.0, 5.0, 2.0}
+	due := []float64{6.0, 3.0, 17.0, 1.0, 12.0, 8.0}
+	tardiness := func(s State) float64 {
+		order := s.([]int)
+		res := 0.0
+		end := 0.0
+		for _, job := range order {
+			end = end + duration[job]
+			res = res + math.Max(0.0, end-due[job])
+		}
+		return res
+	}
+	swap := func(s State, r *rand.Rand) State {
+		order := append([]int(nil), s.([]int)...)
+		i := r.Intn(len(order))
+		j := r.Intn(len(order))
+		order[i], order[j] = order[j], order[i]
+		return order
+	}
+	params := &Params{Max_iter: 5000, T_0: 5.0, Cooling: Lam}
+	best := Anneal(tardiness, []int{0, 1, 2, 3, 4, 5}, swap, params)
+	t.Log(best)
+	t.Log(tardiness(best))
+	// the optimum by enumerating all orders
+	optimum := math.Inf(1)
+	var enumerate func(order []int, k int)
+	enumerate = func(order []int, k int) {
+		if k == len(order) {
+			optimum = math.Min(optimum, tardiness(order))
+			return
+		}
+		for i := k; i < len(order); i++ {
+			order[k], order[i] = order[i], order[k]
+			enumerate(order, k+1)
+			order[k], order[i] = order[i], order[k]
+		}
+	}
+	enumerate([]int{0, 1, 2, 3, 4, 5}, 0)
+	if tardiness(best) != optimum {
+		t.Fatal("TestAnnealScheduling fails", tardiness(best), optimum)
+	}
+}
+
+func TestSaDefaults(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-10.0, -10.0})
+	b_up := mat.NewVecDense(2, []float64{10.0, 10.0})
+	sphere := func(x mat.Vector) float64 {
+		return math.Pow(x.AtVec(0), 2.0) + math.Pow(x.AtVec(1), 2.0)
+	}
+	min := Optimize(sphere, b_low, b_up, &Params{Max_iter: 5000, T_0: 1.0})
+	t.Log(min, sphere(min))
+	if sphere(min) > 0.1 {
+		t.Fatal("TestSaDefaults fails")
+	}
+}
@@ -0,0 +1,310 @@
+package sampling
+
+import (
+	"math"
+
+	"github.com/applied-math-coding/heuristic/common"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Sampler returns n points within b_low and b_up. It is used as the Init option of the optimizers,
+// where nil means Uniform.
+type Sampler = func(b_low mat.Vector, b_up mat.Vector, n int) []*mat.VecDense
+
+// Uniform draws i.i.d. uniform points.
+func Uniform(b_low mat.Vector, b_up mat.Vector, n int) []*mat.VecDense {
+	res := make([]*mat.VecDense, n)
+	for i := range res {
+		res[i] = common.RandomDataInBounds(b_low, b_up)
+	}
+	return res
+}
+
+// Sample calls init or Uniform if init is nil.
+func Sample(init Sampler, b_low mat.Vector, b_up mat.Vector, n int) []*mat.VecDense {
+	if init == nil {
+		return Uniform(b_low, b_up, n)
+	}
+	return init(b_low, b_up, n)
+}
+
+// maximal number of swaps of LatinHypercube
+const maxSwaps = 2000
+
+// LatinHypercube places exactly one point into each of the n slices of every dimension.
+// The design is improved towards maximin distance by swapping slices between points (Morris, Mitchell 1995),
+// with 20 n but at most maxSwaps swaps.
+func LatinHypercube(b_low mat.Vector, b_up mat.Vector, n int) []*mat.VecDense {
+	r := common.GetNewRand()
+	d := b_low.Len()
+	// u[i][k] lies in the slice of point i in dimension k
+	u := make([][]float64, n)
+	for i := range u {
+		u[i] = make([]float64, d)
+	}
+	for k := 0; k < d; k++ {
+		for i, slice := range r.Perm(n) {
+			u[i][k] = (float64(slice) + r.Float64()) / float64(n)
+		}
+	}
+	// squared distances, near[i] is the one of point i to its nearest neighbour nearest[i]
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		for j := 0; j < i; j++ {
+			s := 0.0
+			for k := 0; k < d; k++ {
+				s = s + (u[i][k]-u[j][k])*(u[i][k]-u[j][k])
+			}
+			dist[i][j] = s
+			dist[j][i] = s
+		}
+	}
+	near := make([]float64, n)
+	nearest := make([]int, n)
+	updateNearest := func(i int) {
+		near[i] = math.Inf(1)
+		for j := 0; j < n; j++ {
+			if j != i && dist[i][j] < near[i] {
+				near[i] = dist[i][j]
+				nearest[i] = j
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		updateNearest(i)
+	}
+	// swapping the coordinates k of i and j only changes the rows i and j in the k-th term
+	swap := func(i int, j int, k int) {
+		u_i, u_j := u[i][k], u[j][k]
+		for l := 0; l < n; l++ {
+			if l == i || l == j {
+				continue
+			}
+			delta := (u_j-u[l][k])*(u_j-u[l][k]) - (u_i-u[l][k])*(u_i-u[l][k])
+			dist[i][l] = dist[i][l] + delta
+			dist[l][i] = dist[i][l]
+			dist[j][l] = dist[j][l] - delta
+			dist[l][j] = dist[j][l]
+		}
+		u[i][k], u[j][k] = u_j, u_i
+	}
+	min := minOf(near)
+	old_near := make([]float64, n)
+	old_nearest := make([]int, n)
+	swaps := 20 * n
+	if swaps > maxSwaps {
+		swaps = maxSwaps
+	}
+	for iter := 0; n > 1 && iter < swaps; iter++ {
+		k := r.Intn(d)
+		i := r.Intn(n)
+		j := r.Intn(n - 1)
+		if j >= i {
+			j++
+		}
+		copy(old_near, near)
+		copy(old_nearest, nearest)
+		swap(i, j, k)
+		for l := 0; l < n; l++ {
+			if l == i || l == j || nearest[l] == i || nearest[l] == j {
+				updateNearest(l)
+				continue
+			}
+			if dist[l][i] < near[l] {
+				near[l], nearest[l] = dist[l][i], i
+			}
+			if dist[l][j] < near[l] {
+				near[l], nearest[l] = dist[l][j], j
+			}
+		}
+		if m := minOf(near); m >= min {
+			min = m
+			continue
+		}
+		swap(i, j, k)
+		copy(near, old_near)
+		copy(nearest, old_nearest)
+	}
+	res := make([]*mat.VecDense, n)
+	for i := range res {
+		res[i] = scale(u[i], b_low, b_up)
+	}
+	return res
+}
+
+// Halton uses the radical inverses in the first d primes, starting with the index 1.
+func Halton(b_low mat.Vector, b_up mat.Vector, n int) []*mat.VecDense {
+	primes := firstPrimes(b_low.Len())
+	res := make([]*mat.VecDense, n)
+	for i := range res {
+		u := make([]float64, len(primes))
+		for k, p := range primes {
+			u[k] = radicalInverse(i+1, p)
+		}
+		res[i] = scale(u, b_low, b_up)
+	}
+	return res
+}
+
+// Sobol uses the direction numbers of Joe, Kuo 2008 for up to 21 dimensions and falls back to Halton beyond.
+// The first point of the sequence (the lower bound) is skipped.
+func Sobol(b_low mat.Vector, b_up mat.Vector, n int) []*mat.VecDense {
+	d := b_low.Len()
+	if d > len(sobolDirections)+1 {
+		return Halton(b_low, b_up, n)
+	}
+	const bits = 32
+	v := make([][]uint32, d)
+	for k := range v {
+		v[k] = make([]uint32, bits)
+		if k == 0 {
+			for i := 0; i < bits; i++ {
+				v[k][i] = 1 << uint(bits-1-i)
+			}
+			continue
+		}
+		dir := sobolDirections[k-1]
+		s := len(dir.m)
+		for i := 0; i < bits; i++ {
+			if i < s {
+				v[k][i] = dir.m[i] << uint(bits-1-i)
+				continue
+			}
+			v[k][i] = v[k][i-s] ^ (v[k][i-s] >> uint(s))
+			for j := 1; j < s; j++ {
+				if (dir.a>>uint(s-1-j))&1 == 1 {
+					v[k][i] = v[k][i] ^ v[k][i-j]
+				}
+			}
+		}
+	}
+	x := make([]uint32, d)
+	res := make([]*mat.VecDense, n)
+	for i := range res {
+		// Gray code order, the rightmost zero bit of i selects the direction number
+		c := 0
+		for (i>>uint(c))&1 == 1 {
+			c++
+		}
+		u := make([]float64, d)
+		for k := range x {
+			x[k] = x[k] ^ v[k][c]
+			u[k] = float64(x[k]) / math.Pow(2.0, bits)
+		}
+		res[i] = scale(u, b_low, b_up)
+	}
+	return res
+}
+
+type direction = struct {
+	a uint32   // coefficients of the primitive polynomial
+	m []uint32 // initial direction numbers, the degree is len(m)
+}
+
+// new-joe-kuo-6.21201 for the dimensions 2 to 21
+var sobolDirections = []direction{
+	{0, []uint32{1}},
+	{1, []uint32{1, 3}},
+	{1, []uint32{1, 3, 1}},
+	{2, []uint32{1, 1, 1}},
+	{1, []uint32{1, 1, 3, 3}},
+	{4, []uint32{1, 3, 5, 13}},
+	{2, []uint32{1, 1, 5, 5, 17}},
+	{4, []uint32{1, 1, 5, 5, 5}},
+	{7, []uint32{1, 1, 7, 11, 19}},
+	{11, []uint32{1, 1, 5, 1, 1}},
+	{13, []uint32{1, 1, 1, 3, 11}},
+	{14, []uint32{1, 3, 5, 5, 31}},
+	{1, []uint32{1, 3, 3, 9, 7, 49}},
+	{13, []uint32{1, 1, 1, 15, 21, 21}},
+	{16, []uint32{1, 3, 1, 13, 27, 49}},
+	{19, []uint32{1, 1, 1, 15, 7, 5}},
+	{22, []uint32{1, 3, 1, 15, 13, 25}},
+	{25, []uint32{1, 1, 5, 5, 19, 61}},
+	{1, []uint32{1, 3, 7, 11, 23, 15, 103}},
+	{4, []uint32{1, 3, 7, 13, 13, 15, 69}},
+}
+
+func minOf(values []float64) float64 {
+	res := math.Inf(1)
+	for _, v := range values {
+		res = math.Min(res, v)
+	}
+	return res
+}
+
+func radicalInverse(i int, base int) float64 {
+	res := 0.0
+	f := 1.0 / float64(base)
+	for ; i > 0; i = i / base {
+		res = res + f*float64(i%base)
+		f = f / float64(base)
+	}
+	return res
+}
+
+func firstPrimes(d int) []int {
+	res := make([]int, 0, d)
+	for c := 2; len(res) < d; c++ {
+		prime := true
+		for _, p := range res {
+			if p*p > c {
+				break
+			}
+			if c%p == 0 {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// maps u of the unit cube into the box
+func scale(u []float64, b_low mat.Vector, b_up mat.Vector) *mat.VecDense {
+	v := mat.NewVecDense(len(u), u)
+	common.ApplyElementwise(v, func(e float64, idx int) float64 {
+		return b_low.AtVec(idx) + e*(b_up.AtVec(idx)-b_low.AtVec(idx))
+	})
+	return v
+}
+
+// Discrepancy is the centered L2-discrepancy of the points mapped into the unit cube (Hickernell 1998),
+// smaller values mean a more uniform coverage.
+func Discrepancy(points []*mat.VecDense, b_low mat.Vector, b_up mat.Vector) float64 {
+	n := float64(len(points))
+	d := b_low.Len()
+	u := make([][]float64, len(points))
+	for i, x := range points {
+		u[i] = make([]float64, d)
+		for k := 0; k < d; k++ {
+			u[i][k] = (x.AtVec(k) - b_low.AtVec(k)) / (b_up.AtVec(k) - b_low.AtVec(k))
+		}
+	}
+	sum_1 := 0.0
+	for _, e := range u {
+		prod := 1.0
+		for _, c := range e {
+			a := math.Abs(c - 0.5)
+			prod = prod * (1.0 + 0.5*a - 0.5*a*a)
+		}
+		sum_1 = sum_1 + prod
+	}
+	sum_2 := 0.0
+	for _, e := range u {
+		for _, g := range u {
+			prod := 1.0
+			for k := range e {
+				a, b := math.Abs(e[k]-0.5), math.Abs(g[k]-0.5)
+				prod = prod * (1.0 + 0.5*a + 0.5*b - 0.5*math.Abs(e[k]-g[k]))
+			}
+			sum_2 = sum_2 + prod
+		}
+	}
+	return math.Sqrt(math.Pow(13.0/12.0, float64(d)) - 2.0/n*sum_1 + sum_2/(n*n))
+}
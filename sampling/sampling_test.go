@@ -0,0 +1,87 @@
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSobol(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{0.0, 0.0})
+	b_up := mat.NewVecDense(2, []float64{1.0, 1.0})
+	expected := [][]float64{{0.5, 0.5}, {0.75, 0.25}, {0.25, 0.75}, {0.375, 0.375}, {0.875, 0.875},
+		{0.625, 0.125}, {0.125, 0.625}}
+	for i, x := range Sobol(b_low, b_up, len(expected)) {
+		if x.AtVec(0) != expected[i][0] || x.AtVec(1) != expected[i][1] {
+			t.Fatal(i, x, expected[i])
+		}
+	}
+}
+
+func TestSobolHighDimension(t *testing.T) {
+	b_low := mat.NewVecDense(30, nil)
+	b_up := mat.NewVecDense(30, nil)
+	for i := 0; i < 30; i++ {
+		b_up.SetVec(i, 1.0)
+	}
+	points := Sobol(b_low, b_up, 10)
+	if len(points) != 10 || points[0].Len() != 30 {
+		t.Fatal("TestSobolHighDimension fails")
+	}
+}
+
+func TestHalton(t *testing.T) {
+	b_low := mat.NewVecDense(2, []float64{-1.0, 0.0})
+	b_up := mat.NewVecDense(2, []float64{1.0, 3.0})
+	points := Halton(b_low, b_up, 3)
+	t.Log(points[0], points[1], points[2])
+	if points[0].AtVec(0) != 0.0 || points[0].AtVec(1) != 1.0 {
+		t.Fatal(points[0])
+	}
+}
+
+func TestLatinHypercube(t *testing.T) {
+	n := 20
+	b_low := mat.NewVecDense(3, []float64{-5.0, 0.0, 10.0})
+	b_up := mat.NewVecDense(3, []float64{5.0, 1.0, 20.0})
+	points := LatinHypercube(b_low, b_up, n)
+	for k := 0; k < 3; k++ {
+		occupied := make(map[int]bool)
+		for _, x := range points {
+			u := (x.AtVec(k) - b_low.AtVec(k)) / (b_up.AtVec(k) - b_low.AtVec(k))
+			occupied[int(u*float64(n))] = true
+		}
+		if len(occupied) != n {
+			t.Fatal("dimension", k, "covers only", len(occupied), "slices")
+		}
+	}
+}
+
+func TestLatinHypercubeLarge(t *testing.T) {
+	n := 1000
+	b_low := mat.NewVecDense(30, nil)
+	b_up := mat.NewVecDense(30, nil)
+	for i := 0; i < 30; i++ {
+		b_up.SetVec(i, 1.0)
+	}
+	start := time.Now()
+	points := LatinHypercube(b_low, b_up, n)
+	t.Log(time.Since(start))
+	occupied := make(map[int]bool)
+	for _, x := range points {
+		occupied[int(x.AtVec(29)*float64(n))] = true
+	}
+	if len(occupied) != n {
+		t.Fatal("covers only", len(occupied), "slices")
+	}
+}
+
+func TestDiscrepancy(t *testing.T) {
+	b_low := mat.NewVecDense(5, nil)
+	b_up := mat.NewVecDense(5, []float64{1.0, 1.0, 1.0, 1.0, 1.0})
+	samplers := map[string]Sampler{"uniform": Uniform, "lhs": LatinHypercube, "halton": Halton, "sobol": Sobol}
+	for name, s := range samplers {
+		t.Log(name, Discrepancy(s(b_low, b_up, 64), b_low, b_up))
+	}
+}
@@ -0,0 +1,203 @@
+package tsplib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Problem is a symmetric or asymmetric TSP instance.
+// Supported edge weight types are EUC_2D, CEIL_2D, MAN_2D, ATT, GEO and EXPLICIT with the formats
+// FULL_MATRIX, UPPER_ROW, LOWER_ROW, UPPER_DIAG_ROW and LOWER_DIAG_ROW.
+type Problem = struct {
+	Name      string
+	Type      string
+	Dimension int
+	Dist      *mat.Dense
+}
+
+func ReadFile(path string) (*Problem, error) {
+	file, errOpen := os.Open(path)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+func Read(r io.Reader) (*Problem, error) {
+	header := make(map[string]string)
+	var coords [][]float64
+	var weights []float64
+	scanner := bufio.NewScanner(r)
+	section := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "EOF" {
+			break
+		}
+		if strings.HasSuffix(line, "_SECTION") {
+			section = line
+			continue
+		}
+		if idx := strings.Index(line, ":"); idx >= 0 && isHeaderKey(line[:idx]) {
+			header[strings.TrimSpace(line[:idx])] = strings.TrimSpace(line[idx+1:])
+			section = ""
+			continue
+		}
+		fields, errParse := parseFields(line)
+		if errParse != nil {
+			return nil, errParse
+		}
+		switch section {
+		case "NODE_COORD_SECTION":
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("tsplib: invalid node line %q", line)
+			}
+			coords = append(coords, fields[1:3])
+		case "EDGE_WEIGHT_SECTION":
+			weights = append(weights, fields...)
+		}
+	}
+	if errScan := scanner.Err(); errScan != nil {
+		return nil, errScan
+	}
+	n, errDim := strconv.Atoi(header["DIMENSION"])
+	if errDim != nil {
+		return nil, fmt.Errorf("tsplib: invalid DIMENSION %q", header["DIMENSION"])
+	}
+	var dist *mat.Dense
+	var errDist error
+	if header["EDGE_WEIGHT_TYPE"] == "EXPLICIT" {
+		dist, errDist = explicitDist(n, header["EDGE_WEIGHT_FORMAT"], weights)
+	} else {
+		dist, errDist = coordDist(n, header["EDGE_WEIGHT_TYPE"], coords)
+	}
+	if errDist != nil {
+		return nil, errDist
+	}
+	return &Problem{Name: header["NAME"], Type: header["TYPE"], Dimension: n, Dist: dist}, nil
+}
+
+func isHeaderKey(key string) bool {
+	key = strings.TrimSpace(key)
+	return key != "" && strings.ToUpper(key) == key && !strings.ContainsAny(key, " 0123456789.-")
+}
+
+func parseFields(line string) ([]float64, error) {
+	parts := strings.Fields(line)
+	res := make([]float64, len(parts))
+	for i, p := range parts {
+		v, errParse := strconv.ParseFloat(p, 64)
+		if errParse != nil {
+			return nil, fmt.Errorf("tsplib: invalid number %q", p)
+		}
+		res[i] = v
+	}
+	return res, nil
+}
+
+func coordDist(n int, weight_type string, coords [][]float64) (*mat.Dense, error) {
+	if len(coords) != n {
+		return nil, fmt.Errorf("tsplib: expected %d nodes, found %d", n, len(coords))
+	}
+	var d func(a []float64, b []float64) float64
+	switch weight_type {
+	case "EUC_2D":
+		d = func(a []float64, b []float64) float64 {
+			return math.Round(math.Hypot(a[0]-b[0], a[1]-b[1]))
+		}
+	case "CEIL_2D":
+		d = func(a []float64, b []float64) float64 {
+			return math.Ceil(math.Hypot(a[0]-b[0], a[1]-b[1]))
+		}
+	case "MAN_2D":
+		d = func(a []float64, b []float64) float64 {
+			return math.Round(math.Abs(a[0]-b[0]) + math.Abs(a[1]-b[1]))
+		}
+	case "ATT":
+		d = func(a []float64, b []float64) float64 {
+			r := math.Sqrt((math.Pow(a[0]-b[0], 2.0) + math.Pow(a[1]-b[1], 2.0)) / 10.0)
+			t := math.Round(r)
+			if t < r {
+				return t + 1.0
+			}
+			return t
+		}
+	case "GEO":
+		d = geoDist
+	default:
+		return nil, fmt.Errorf("tsplib: unsupported EDGE_WEIGHT_TYPE %q", weight_type)
+	}
+	dist := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j {
+				dist.Set(i, j, d(coords[i], coords[j]))
+			}
+		}
+	}
+	return dist, nil
+}
+
+func geoDist(a []float64, b []float64) float64 {
+	rad := func(x float64) float64 {
+		deg := math.Trunc(x)
+		return math.Pi * (deg + 5.0*(x-deg)/3.0) / 180.0
+	}
+	lat_a, long_a := rad(a[0]), rad(a[1])
+	lat_b, long_b := rad(b[0]), rad(b[1])
+	q1 := math.Cos(long_a - long_b)
+	q2 := math.Cos(lat_a - lat_b)
+	q3 := math.Cos(lat_a + lat_b)
+	return math.Trunc(6378.388*math.Acos(0.5*((1.0+q1)*q2-(1.0-q1)*q3)) + 1.0)
+}
+
+func explicitDist(n int, format string, weights []float64) (*mat.Dense, error) {
+	dist := mat.NewDense(n, n, nil)
+	k := 0
+	next := func() (float64, error) {
+		if k >= len(weights) {
+			return 0.0, fmt.Errorf("tsplib: too few edge weights for %s", format)
+		}
+		k++
+		return weights[k-1], nil
+	}
+	for i := 0; i < n; i++ {
+		var from, to int
+		switch format {
+		case "FULL_MATRIX":
+			from, to = 0, n
+		case "UPPER_ROW":
+			from, to = i+1, n
+		case "UPPER_DIAG_ROW":
+			from, to = i, n
+		case "LOWER_ROW":
+			from, to = 0, i
+		case "LOWER_DIAG_ROW":
+			from, to = 0, i+1
+		default:
+			return nil, fmt.Errorf("tsplib: unsupported EDGE_WEIGHT_FORMAT %q", format)
+		}
+		for j := from; j < to; j++ {
+			w, errNext := next()
+			if errNext != nil {
+				return nil, errNext
+			}
+			dist.Set(i, j, w)
+			if format != "FULL_MATRIX" {
+				dist.Set(j, i, w)
+			}
+		}
+	}
+	return dist, nil
+}
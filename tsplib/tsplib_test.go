@@ -0,0 +1,49 @@
+package tsplib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadCoords(t *testing.T) {
+	data := `NAME : square4
+TYPE : TSP
+COMMENT : four corners
+DIMENSION : 4
+EDGE_WEIGHT_TYPE : EUC_2D
+NODE_COORD_SECTION
+1 0 0
+2 10 0
+3 10 10
+4 0 10
+EOF
+`
+	problem, errRead := Read(strings.NewReader(data))
+	if errRead != nil {
+		t.Fatal(errRead)
+	}
+	t.Log(problem.Name)
+	if problem.Dist.At(0, 2) != 14.0 {
+		t.Fatal("TestReadCoords fails")
+	}
+}
+
+func TestReadExplicit(t *testing.T) {
+	data := `NAME: tiny
+TYPE: TSP
+DIMENSION: 3
+EDGE_WEIGHT_TYPE: EXPLICIT
+EDGE_WEIGHT_FORMAT: UPPER_ROW
+EDGE_WEIGHT_SECTION
+ 5 7
+ 3
+EOF
+`
+	problem, errRead := Read(strings.NewReader(data))
+	if errRead != nil {
+		t.Fatal(errRead)
+	}
+	if problem.Dist.At(2, 0) != 7.0 || problem.Dist.At(1, 2) != 3.0 {
+		t.Fatal("TestReadExplicit fails")
+	}
+}